@@ -19,17 +19,11 @@ func BlockReporter(id, path string) (bool, string) {
 	fmt.Printf("Generando reporte para partición: %s en disco: %s\n", mountedPartition.ID, mountedPartition.DiskPath)
 
 	// 2. Abrir el disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, size, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, fmt.Sprintf("Error al abrir el disco: %s", err)
 	}
 	defer file.Close()
-
-	// 3. Obtener detalles de la partición
-	startByte, size, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, fmt.Sprintf("Error al obtener detalles de la partición: %s", err)
-	}
 	fmt.Printf("Partición inicia en byte: %d, tamaño: %d bytes\n", startByte, size)
 
 	// 4. Leer el superbloque
@@ -125,6 +119,11 @@ func BlockReporter(id, path string) (bool, string) {
 			continue
 		}
 
+		// Los bloques del inodo de bloques dañados no tienen contenido que mostrar
+		if i == EXT2_BAD_BLOCKS_INODE {
+			continue
+		}
+
 		// Leer este inodo
 		inodePos := inodesStartPos + int64(i)*int64(superblock.SInodeSize)
 		_, err = file.Seek(inodePos, 0)
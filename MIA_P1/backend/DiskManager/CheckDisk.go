@@ -0,0 +1,357 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Severidades de los hallazgos de checkdisk
+const (
+	SEVERITY_ERROR   = "ERROR"
+	SEVERITY_WARNING = "ADVERTENCIA"
+	SEVERITY_INFO    = "INFO"
+)
+
+// DiskFinding es un problema encontrado en la tabla de particiones
+type DiskFinding struct {
+	Severity  string `json:"severity"`
+	Code      string `json:"code"`
+	Partition string `json:"partition,omitempty"`
+	Message   string `json:"message"`
+	Repaired  bool   `json:"repaired"`
+}
+
+// DiskCheckResult es el resultado de revisar un disco
+type DiskCheckResult struct {
+	Path     string        `json:"path"`
+	Findings []DiskFinding `json:"findings"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Repaired int           `json:"repaired"`
+}
+
+func (r *DiskCheckResult) add(severity, code, partition, message string, repaired bool) {
+	r.Findings = append(r.Findings, DiskFinding{
+		Severity:  severity,
+		Code:      code,
+		Partition: partition,
+		Message:   message,
+		Repaired:  repaired,
+	})
+	switch severity {
+	case SEVERITY_ERROR:
+		r.Errors++
+	case SEVERITY_WARNING:
+		r.Warnings++
+	}
+	if repaired {
+		r.Repaired++
+	}
+}
+
+// checkedRange es una zona ocupada del disco usada para detectar superposiciones
+type checkedRange struct {
+	name  string
+	start int64
+	end   int64
+}
+
+// CheckDisk revisa la consistencia del MBR y de la cadena de EBRs de un disco.
+// Con repair=true corrige solo lo que no pone en riesgo datos: slots huérfanos,
+// ajustes inválidos, particiones que pasan el final del disco y cadenas de EBR
+// con ciclos o punteros fuera de la extendida (se cortan en ese punto).
+func CheckDisk(diskPath string, repair bool) (*DiskCheckResult, error) {
+	if repair {
+		for _, mp := range utils.MountedPartitions {
+			if mp.UsesDisk(diskPath) {
+				return nil, fmt.Errorf("no se puede reparar el disco: la partición '%s' está montada con ID %s", mp.PartitionName, mp.ID)
+			}
+		}
+	}
+
+	// Con repair se modifica el disco y se necesita el candado exclusivo
+	unlock, err := LockDisk(diskPath, repair)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	flags := os.O_RDONLY
+	if repair {
+		flags = os.O_RDWR
+	}
+	file, err := os.OpenFile(diskPath, flags, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo información del archivo: %v", err)
+	}
+
+	mbr := &MBR{}
+	if err := binary.Read(file, binary.LittleEndian, mbr); err != nil {
+		return nil, fmt.Errorf("error leyendo MBR: %v", err)
+	}
+
+	result := &DiskCheckResult{Path: diskPath, Findings: []DiskFinding{}}
+	mbrSize := int64(binary.Size(mbr))
+	mbrChanged := false
+
+	// 1. Datos generales del disco
+	if mbr.MbrTamanio != stat.Size() {
+		result.add(SEVERITY_WARNING, "DISK_SIZE", "",
+			fmt.Sprintf("el MBR indica %d bytes pero el archivo tiene %d bytes", mbr.MbrTamanio, stat.Size()), false)
+	}
+	if !isValidFit(mbr.DskFit) {
+		message := fmt.Sprintf("el ajuste del disco es inválido (%d)", mbr.DskFit)
+		fixed := false
+		if repair {
+			mbr.DskFit = FIT_FIRST
+			mbrChanged, fixed = true, true
+		}
+		result.add(SEVERITY_WARNING, "DISK_FIT", "",
+			message, fixed)
+	}
+
+	// 2. Slots del MBR
+	var ranges []checkedRange
+	names := make(map[string]int)
+	extendedIndex := -1
+
+	for i := range mbr.MbrPartitions {
+		p := &mbr.MbrPartitions[i]
+		pName := strings.TrimRight(string(p.Name[:]), " \x00")
+		slot := fmt.Sprintf("slot %d", i+1)
+		if pName != "" {
+			slot = pName
+		}
+
+		if p.Size == 0 {
+			// Slot vacío con restos de una partición
+			if pName != "" || p.Type == PARTITION_PRIMARY || p.Type == PARTITION_EXTENDED {
+				message := fmt.Sprintf("el slot %d no tiene tamaño pero conserva datos de una partición", i+1)
+				fixed := false
+				if repair {
+					*p = emptyPartitionSlot()
+					mbrChanged, fixed = true, true
+				}
+				result.add(SEVERITY_WARNING, "ORPHAN_SLOT", slot,
+					message, fixed)
+			}
+			continue
+		}
+
+		// Slots que no se pueden interpretar como partición
+		if p.Size < 0 || p.Start < mbrSize || p.Start >= mbr.MbrTamanio ||
+			(p.Type != PARTITION_PRIMARY && p.Type != PARTITION_EXTENDED) {
+			message := fmt.Sprintf("el slot %d es inválido (Type=%d, Start=%d, Size=%d)", i+1, p.Type, p.Start, p.Size)
+			fixed := false
+			if repair {
+				*p = emptyPartitionSlot()
+				mbrChanged, fixed = true, true
+			}
+			result.add(SEVERITY_ERROR, "INVALID_SLOT", slot,
+				message, fixed)
+			continue
+		}
+
+		if p.Start+p.Size > mbr.MbrTamanio {
+			message := fmt.Sprintf("la partición termina en %d, después del final del disco (%d)", p.Start+p.Size, mbr.MbrTamanio)
+			fixed := false
+			if repair {
+				p.Size = mbr.MbrTamanio - p.Start
+				mbrChanged, fixed = true, true
+			}
+			result.add(SEVERITY_ERROR, "PAST_END", slot,
+				message, fixed)
+		}
+
+		if !isValidFit(p.Fit) {
+			message := fmt.Sprintf("el ajuste de la partición es inválido (%d)", p.Fit)
+			fixed := false
+			if repair {
+				p.Fit = FIT_FIRST
+				mbrChanged, fixed = true, true
+			}
+			result.add(SEVERITY_WARNING, "INVALID_FIT", slot,
+				message, fixed)
+		}
+
+		if p.Type == PARTITION_EXTENDED {
+			if extendedIndex != -1 {
+				result.add(SEVERITY_ERROR, "MULTIPLE_EXTENDED", slot,
+					"existe más de una partición extendida en el disco", false)
+			} else {
+				extendedIndex = i
+			}
+		}
+
+		names[pName]++
+		ranges = append(ranges, checkedRange{name: slot, start: p.Start, end: p.Start + p.Size})
+	}
+
+	// 3. Superposición entre particiones del MBR
+	checkOverlaps(result, ranges)
+
+	// 4. Cadena de EBRs de la extendida
+	if extendedIndex != -1 {
+		ext := mbr.MbrPartitions[extendedIndex]
+		logicalNames, err := checkEBRChain(file, result, &ext, repair)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range logicalNames {
+			names[name]++
+		}
+	}
+
+	// 5. Nombres repetidos
+	for name, count := range names {
+		if count > 1 {
+			result.add(SEVERITY_ERROR, "DUPLICATE_NAME", name,
+				fmt.Sprintf("el nombre '%s' está repetido en %d particiones", name, count), false)
+		}
+	}
+
+	if mbrChanged {
+		if _, err := file.Seek(0, 0); err != nil {
+			return nil, fmt.Errorf("error posicionando cursor para MBR: %v", err)
+		}
+		if err := binary.Write(file, binary.LittleEndian, mbr); err != nil {
+			return nil, fmt.Errorf("error escribiendo MBR: %v", err)
+		}
+	}
+
+	if len(result.Findings) == 0 {
+		result.add(SEVERITY_INFO, "OK", "", "no se encontraron problemas en la tabla de particiones", false)
+	}
+
+	return result, nil
+}
+
+// checkEBRChain revisa la cadena de EBRs y retorna los nombres de las lógicas encontradas
+func checkEBRChain(file *os.File, result *DiskCheckResult, ext *Partition, repair bool) ([]string, error) {
+	var names []string
+	var ranges []checkedRange
+	ebrSize := int64(binary.Size(EBR{}))
+	extEnd := ext.Start + ext.Size
+	visited := make(map[int64]bool)
+
+	currentPos := ext.Start
+	for currentPos != -1 {
+		visited[currentPos] = true
+
+		if _, err := file.Seek(currentPos, 0); err != nil {
+			return nil, fmt.Errorf("error posicionando cursor para EBR: %v", err)
+		}
+		ebr := &EBR{}
+		if err := binary.Read(file, binary.LittleEndian, ebr); err != nil {
+			result.add(SEVERITY_ERROR, "EBR_UNREADABLE", "",
+				fmt.Sprintf("no se pudo leer el EBR en la posición %d", currentPos), false)
+			break
+		}
+
+		// Una extendida recién creada queda en ceros: EBR vacío sin cadena
+		if currentPos == ext.Start && ebr.Size == 0 && ebr.Next == 0 {
+			break
+		}
+
+		ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
+		label := ebrName
+		if label == "" {
+			label = fmt.Sprintf("EBR en %d", currentPos)
+		}
+		ebrChanged := false
+
+		if ebr.Size > 0 {
+			if ebr.Size < ebrSize {
+				result.add(SEVERITY_ERROR, "LOGICAL_TOO_SMALL", label,
+					fmt.Sprintf("la lógica mide %d bytes, menos que su propio EBR (%d bytes)", ebr.Size, ebrSize), false)
+			}
+			if currentPos+ebr.Size > extEnd {
+				result.add(SEVERITY_ERROR, "LOGICAL_DOES_NOT_FIT", label,
+					fmt.Sprintf("la lógica termina en %d, fuera de la extendida (termina en %d)", currentPos+ebr.Size, extEnd), false)
+			}
+			if ebr.Start != currentPos {
+				message := fmt.Sprintf("el EBR indica Start=%d pero está escrito en %d", ebr.Start, currentPos)
+				fixed := false
+				if repair {
+					ebr.Start = currentPos
+					ebrChanged, fixed = true, true
+				}
+				result.add(SEVERITY_WARNING, "EBR_START_MISMATCH", label,
+					message, fixed)
+			}
+			if !isValidFit(ebr.Fit) {
+				message := fmt.Sprintf("el ajuste de la lógica es inválido (%d)", ebr.Fit)
+				fixed := false
+				if repair {
+					ebr.Fit = FIT_FIRST
+					ebrChanged, fixed = true, true
+				}
+				result.add(SEVERITY_WARNING, "INVALID_FIT", label,
+					message, fixed)
+			}
+
+			names = append(names, ebrName)
+			ranges = append(ranges, checkedRange{name: label, start: currentPos, end: currentPos + ebr.Size})
+		}
+
+		// Validar el enlace al siguiente EBR
+		next := ebr.Next
+		if next != -1 {
+			problem := ""
+			code := ""
+			if next < ext.Start || next+ebrSize > extEnd {
+				code = "EBR_NEXT_OUT_OF_RANGE"
+				problem = fmt.Sprintf("el puntero Next (%d) está fuera de la extendida [%d, %d)", next, ext.Start, extEnd)
+			} else if visited[next] {
+				code = "EBR_CYCLE"
+				problem = fmt.Sprintf("el puntero Next (%d) vuelve a un EBR ya visitado, la cadena tiene un ciclo", next)
+			}
+
+			if problem != "" {
+				fixed := false
+				if repair {
+					ebr.Next = -1
+					ebrChanged, fixed = true, true
+				}
+				result.add(SEVERITY_ERROR, code, label, problem, fixed)
+				next = -1
+			}
+		}
+
+		if ebrChanged {
+			if _, err := file.Seek(currentPos, 0); err != nil {
+				return nil, fmt.Errorf("error posicionando cursor para EBR: %v", err)
+			}
+			if err := binary.Write(file, binary.LittleEndian, ebr); err != nil {
+				return nil, fmt.Errorf("error escribiendo EBR: %v", err)
+			}
+		}
+
+		currentPos = next
+	}
+
+	checkOverlaps(result, ranges)
+	return names, nil
+}
+
+// checkOverlaps reporta las zonas que se superponen entre sí
+func checkOverlaps(result *DiskCheckResult, ranges []checkedRange) {
+	for i := 0; i < len(ranges); i++ {
+		for j := i + 1; j < len(ranges); j++ {
+			a, b := ranges[i], ranges[j]
+			if a.start < b.end && b.start < a.end {
+				result.add(SEVERITY_ERROR, "OVERLAP", a.name,
+					fmt.Sprintf("'%s' [%d, %d) se superpone con '%s' [%d, %d)", a.name, a.start, a.end, b.name, b.start, b.end), false)
+			}
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+// CLONE_CHUNK es el tamaño de bloque al copiar discos y particiones (64 KB).
+// Es pequeño para que los huecos de un disco casi vacío se conserven en el clon.
+const CLONE_CHUNK = 64 * 1024
+
+// CloneDisk copia la imagen srcPath en destPath y le asigna una firma nueva.
+// Los bloques en cero no se escriben, así que el clon queda como archivo sparse aunque
+// el original tenga todo el espacio reservado. Las particiones del clon quedan desmontadas.
+func CloneDisk(srcPath, destPath string) (*MBR, error) {
+	srcMBR, err := ValidateDiskImage(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("el disco origen no es válido: %v", err)
+	}
+
+	src, err := os.OpenFile(srcPath, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco origen: %v", err)
+	}
+	defer src.Close()
+
+	dest, err := os.OpenFile(destPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("ya existe un disco en la ruta: %s", destPath)
+		}
+		return nil, fmt.Errorf("error creando disco destino: %v", err)
+	}
+	defer dest.Close()
+
+	mbr, err := cloneDiskContents(src, dest, srcMBR)
+	if err != nil {
+		dest.Close()
+		os.Remove(destPath)
+		return nil, err
+	}
+
+	name, _ := utils.ExtractDiskInfo(destPath)
+	size, unit := diskSizeAndUnit(mbr.MbrTamanio)
+	RegisterDisk(DiskInfo{
+		Path: destPath,
+		Name: name,
+		Size: size,
+		Unit: unit,
+		Fit:  mbr.DskFit,
+	})
+
+	fmt.Printf("Debug: Disco %s clonado en %s con firma %d\n", srcPath, destPath, mbr.MbrDskSignature)
+	return mbr, nil
+}
+
+// cloneDiskContents copia los bytes del disco y reescribe el MBR y los EBRs del clon
+// con una firma nueva y sin particiones montadas
+func cloneDiskContents(src, dest *os.File, srcMBR *MBR) (*MBR, error) {
+	if err := copyDiskRegion(src, 0, dest, 0, srcMBR.MbrTamanio); err != nil {
+		return nil, fmt.Errorf("error copiando el disco: %v", err)
+	}
+	if err := dest.Truncate(srcMBR.MbrTamanio); err != nil {
+		return nil, fmt.Errorf("error estableciendo tamaño del disco: %v", err)
+	}
+
+	mbr := *srcMBR
+	for mbr.MbrDskSignature == srcMBR.MbrDskSignature {
+		mbr.MbrDskSignature = rand.Int31()
+	}
+
+	// El clon es un disco nuevo: nada de lo que estaba montado en el original lo está aquí
+	pm := &PartitionManager{mbr: &mbr}
+	for i := range mbr.MbrPartitions {
+		p := &mbr.MbrPartitions[i]
+		if p.Size <= 0 {
+			continue
+		}
+		if p.Status == PARTITION_MOUNTED {
+			p.Status = PARTITION_NOT_MOUNTED
+			p.Correlative = -1
+			p.Id = [4]byte{}
+		}
+		if p.Type != PARTITION_EXTENDED {
+			continue
+		}
+		for _, entry := range pm.readLogicalChain(dest, p) {
+			if entry.ebr.Status != PARTITION_MOUNTED {
+				continue
+			}
+			entry.ebr.Status = PARTITION_NOT_MOUNTED
+			if _, err := dest.Seek(entry.pos, 0); err != nil {
+				return nil, fmt.Errorf("error posicionando cursor para EBR: %v", err)
+			}
+			if err := binary.Write(dest, binary.LittleEndian, entry.ebr); err != nil {
+				return nil, fmt.Errorf("error escribiendo EBR: %v", err)
+			}
+		}
+	}
+
+	if _, err := dest.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor para MBR: %v", err)
+	}
+	if err := binary.Write(dest, binary.LittleEndian, &mbr); err != nil {
+		return nil, fmt.Errorf("error escribiendo MBR: %v", err)
+	}
+	if err := dest.Sync(); err != nil {
+		return nil, fmt.Errorf("error sincronizando disco destino: %v", err)
+	}
+
+	return &mbr, nil
+}
+
+// CopyPartition copia los datos de la partición primaria o lógica name del disco srcPath
+// en una partición nueva destName del disco destPath. La partición nueva se crea con el
+// ajuste de la original, como lo haría fdisk, y tiene el mismo tamaño de datos, por lo que
+// el sistema de archivos copiado se puede montar. Si partType es 0 se usa el tipo de la original.
+func CopyPartition(srcPath, name, destPath, destName string, partType byte) (*Partition, error) {
+	srcManager, err := NewPartitionManager(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	src, err := os.OpenFile(srcPath, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco origen: %v", err)
+	}
+	defer src.Close()
+
+	srcData, err := srcManager.findDataRegion(src, name)
+	if err != nil {
+		return nil, err
+	}
+	if partType == 0 {
+		partType = srcData.Type
+	}
+
+	destManager, err := NewPartitionManager(destPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// El tamaño de una lógica incluye su EBR
+	partition := NewPartition()
+	partition.Type = partType
+	partition.Fit = srcData.Fit
+	partition.Size = srcData.Size
+	copy(partition.Name[:], destName)
+	if partType == PARTITION_LOGIC {
+		partition.Size += int64(binary.Size(EBR{}))
+		err = destManager.CreateLogicalPartition(&partition, "B")
+	} else {
+		err = destManager.CreatePartition(&partition, "B")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creando la partición destino: %v", err)
+	}
+
+	dest, err := os.OpenFile(destPath, os.O_RDWR, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco destino: %v", err)
+	}
+	defer dest.Close()
+
+	destData, err := destManager.findDataRegion(dest, destName)
+	if err != nil {
+		return nil, err
+	}
+
+	// La partición nueva ya está en ceros, solo hace falta escribir lo que no lo es
+	if err := copyDiskRegion(src, srcData.Start, dest, destData.Start, srcData.Size); err != nil {
+		return nil, fmt.Errorf("error copiando los datos de la partición: %v", err)
+	}
+
+	fmt.Printf("Debug: Partición '%s' de %s copiada como '%s' en %s (Start=%d, Size=%d)\n",
+		name, srcPath, destName, destPath, destData.Start, destData.Size)
+	return destData, nil
+}
+
+// findDataRegion busca una partición primaria o lógica por nombre y la retorna con
+// Start y Size ajustados a su zona de datos (en una lógica, lo que sigue al EBR)
+func (pm *PartitionManager) findDataRegion(file *os.File, name string) (*Partition, error) {
+	mbr := &MBR{}
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor: %v", err)
+	}
+	if err := binary.Read(file, binary.LittleEndian, mbr); err != nil {
+		return nil, fmt.Errorf("error leyendo MBR: %v", err)
+	}
+
+	ebrSize := int64(binary.Size(EBR{}))
+	for i := range mbr.MbrPartitions {
+		p := mbr.MbrPartitions[i]
+		if p.Size <= 0 {
+			continue
+		}
+		pName := strings.TrimRight(string(p.Name[:]), " \x00")
+		if p.Type == PARTITION_PRIMARY && pName == name {
+			return &p, nil
+		}
+		if p.Type == PARTITION_EXTENDED && pName == name {
+			return nil, fmt.Errorf("la partición '%s' es extendida, solo se pueden copiar primarias y lógicas", name)
+		}
+		if p.Type != PARTITION_EXTENDED {
+			continue
+		}
+		for _, entry := range pm.readLogicalChain(file, &p) {
+			ebrName := strings.TrimRight(string(entry.ebr.Name[:]), " \x00")
+			if entry.ebr.Size <= ebrSize || ebrName != name {
+				continue
+			}
+			logical := NewPartition()
+			logical.Type = PARTITION_LOGIC
+			logical.Fit = entry.ebr.Fit
+			logical.Start = entry.pos + ebrSize
+			logical.Size = entry.ebr.Size - ebrSize
+			logical.Name = entry.ebr.Name
+			return &logical, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no se encontró la partición '%s' en el disco", name)
+}
+
+// copyDiskRegion copia size bytes de src a dest por bloques, saltando los bloques en cero
+func copyDiskRegion(src *os.File, srcStart int64, dest *os.File, destStart int64, size int64) error {
+	buffer := make([]byte, CLONE_CHUNK)
+	zeros := make([]byte, CLONE_CHUNK)
+
+	for offset := int64(0); offset < size; offset += CLONE_CHUNK {
+		chunk := int64(CLONE_CHUNK)
+		if size-offset < chunk {
+			chunk = size - offset
+		}
+		if _, err := src.ReadAt(buffer[:chunk], srcStart+offset); err != nil {
+			return err
+		}
+		if bytes.Equal(buffer[:chunk], zeros[:chunk]) {
+			continue
+		}
+		if _, err := dest.WriteAt(buffer[:chunk], destStart+offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
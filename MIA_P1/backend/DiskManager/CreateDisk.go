@@ -11,8 +11,19 @@ import (
 
 const BUFFER_SIZE = 1024
 
-// CreateDisk crea un archivo binario que simula un disco duro
+// Modos de reserva de espacio para mkdisk -alloc
+const (
+	ALLOC_SPARSE = "sparse" // solo se fija el tamaño, las zonas sin escribir quedan como huecos
+	ALLOC_ZERO   = "zero"   // se escriben ceros en todo el disco
+	ALLOC_FULL   = "full"   // como zero, reportando el avance y sincronizando al terminar
+)
+
+// ZERO_FILL_CHUNK es el tamaño de cada escritura al llenar con ceros (4 MB)
+const ZERO_FILL_CHUNK = 4 * 1024 * 1024
 
+// CreateDisk crea un archivo binario que simula un disco duro.
+// Los huecos de un disco sparse se leen como ceros, así que el contenido es el mismo
+// en los tres modos de reserva; solo cambia cuánto espacio ocupa en el host.
 func CreateDisk(diskConfig utils.DiskConfig) error {
 	// Calcular el tamaño exacto en bytes
 	var totalBytes int64
@@ -57,19 +68,21 @@ func CreateDisk(diskConfig utils.DiskConfig) error {
 		return fmt.Errorf("error estableciendo tamaño del disco: %v", err)
 	}
 
-	// Llenar el resto del disco con ceros (después del MBR)
-	remainingBytes := totalBytes - int64(binary.Size(mbr))
-	if remainingBytes > 0 {
-		zeroBuffer := make([]byte, BUFFER_SIZE)
-		for remainingBytes > 0 {
-			writeSize := BUFFER_SIZE
-			if remainingBytes < int64(BUFFER_SIZE) {
-				writeSize = int(remainingBytes)
-			}
-			if _, err := file.Write(zeroBuffer[:writeSize]); err != nil {
-				return fmt.Errorf("error escribiendo datos al disco: %v", err)
-			}
-			remainingBytes -= int64(writeSize)
+	// Llenar el resto del disco con ceros (después del MBR) según el modo de reserva
+	mbrSize := int64(binary.Size(mbr))
+	switch diskConfig.Alloc {
+	case ALLOC_SPARSE:
+		fmt.Printf("Debug: Disco sparse de %d bytes, no se escriben ceros\n", totalBytes)
+	case ALLOC_FULL:
+		if err := zeroFillWithProgress(file, mbrSize, totalBytes-mbrSize); err != nil {
+			return fmt.Errorf("error escribiendo datos al disco: %v", err)
+		}
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("error sincronizando disco: %v", err)
+		}
+	default:
+		if err := zeroDiskRegion(file, mbrSize, totalBytes-mbrSize); err != nil {
+			return fmt.Errorf("error escribiendo datos al disco: %v", err)
 		}
 	}
 	// Al final, si la creación fue exitosa, registrar el disco
@@ -82,6 +95,33 @@ func CreateDisk(diskConfig utils.DiskConfig) error {
 	return nil
 }
 
+// zeroFillWithProgress escribe ceros en una región del disco reportando el avance cada 10%
+func zeroFillWithProgress(file *os.File, start int64, size int64) error {
+	if _, err := file.Seek(start, 0); err != nil {
+		return err
+	}
+
+	zeroBuffer := make([]byte, ZERO_FILL_CHUNK)
+	var written int64
+	lastReported := int64(-1)
+	for written < size {
+		writeSize := int64(ZERO_FILL_CHUNK)
+		if size-written < writeSize {
+			writeSize = size - written
+		}
+		if _, err := file.Write(zeroBuffer[:writeSize]); err != nil {
+			return err
+		}
+		written += writeSize
+
+		if percent := written * 100 / size; percent/10 > lastReported {
+			lastReported = percent / 10
+			fmt.Printf("Progreso mkdisk: %d%% (%d de %d bytes)\n", percent, written, size)
+		}
+	}
+	return nil
+}
+
 // Función auxiliar para obtener el tipo de ajuste
 func getDiskFit(fit string) byte {
 	switch fit {
@@ -0,0 +1,309 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefragMove describe el desplazamiento de una partición hacia el inicio del disco
+type DefragMove struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	OldStart int64  `json:"oldStart"`
+	NewStart int64  `json:"newStart"`
+	Size     int64  `json:"size"`
+}
+
+// DiskDefragPlan es la propuesta de compactación de un disco
+type DiskDefragPlan struct {
+	Path              string       `json:"path"`
+	DiskSize          int64        `json:"diskSize"`
+	Moves             []DefragMove `json:"moves"`
+	FreeGapsBefore    int          `json:"freeGapsBefore"`
+	LargestFreeBefore int64        `json:"largestFreeBefore"`
+	LargestFreeAfter  int64        `json:"largestFreeAfter"`
+
+	mbr      *MBR            // MBR con las posiciones nuevas
+	extended *Partition      // extendida con su posición original
+	logicals []defragLogical // lógicas en orden de posición
+}
+
+// defragLogical guarda una lógica con su posición actual y la final
+type defragLogical struct {
+	oldPos int64
+	newPos int64
+	ebr    *EBR
+}
+
+// PlanDiskDefrag calcula a dónde se moverían las particiones sin modificar el disco.
+// Las primarias y la extendida se juntan después del MBR en su orden actual y las lógicas
+// se juntan al inicio de la extendida.
+func PlanDiskDefrag(diskPath string) (*DiskDefragPlan, error) {
+	pm, err := NewPartitionManager(diskPath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(diskPath, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	newMBR := *pm.mbr
+	plan := &DiskDefragPlan{
+		Path:     diskPath,
+		DiskSize: pm.mbr.MbrTamanio,
+		mbr:      &newMBR,
+	}
+
+	// 1. Primarias y extendida en el orden en que aparecen en el disco
+	var order []int
+	var reserved []Space
+	for i, p := range newMBR.MbrPartitions {
+		if p.Size > 0 {
+			order = append(order, i)
+			reserved = append(reserved, Space{start: p.Start, size: p.Size})
+		}
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return newMBR.MbrPartitions[order[a]].Start < newMBR.MbrPartitions[order[b]].Start
+	})
+
+	mbrSize := int64(binary.Size(MBR{}))
+	before := freeSpacesBetween(mbrSize, plan.DiskSize, reserved)
+	plan.FreeGapsBefore = len(before)
+	plan.LargestFreeBefore = largestSpace(before)
+
+	cursor := mbrSize
+	for _, i := range order {
+		p := &newMBR.MbrPartitions[i]
+		if p.Type == PARTITION_EXTENDED {
+			original := pm.mbr.MbrPartitions[i]
+			plan.extended = &original
+		}
+		if p.Start != cursor {
+			plan.Moves = append(plan.Moves, DefragMove{
+				Name:     strings.TrimRight(string(p.Name[:]), " \x00"),
+				Type:     string(p.Type),
+				OldStart: p.Start,
+				NewStart: cursor,
+				Size:     p.Size,
+			})
+			p.Start = cursor
+		}
+		cursor = p.Start + p.Size
+	}
+	plan.LargestFreeAfter = plan.DiskSize - cursor
+
+	// 2. Lógicas juntas desde el inicio de la extendida, ya en su posición final
+	if plan.extended != nil {
+		var newExtStart int64
+		for _, p := range newMBR.MbrPartitions {
+			if p.Type == PARTITION_EXTENDED && p.Size > 0 {
+				newExtStart = p.Start
+			}
+		}
+
+		offset := int64(0)
+		for _, entry := range pm.readLogicalChain(file, plan.extended) {
+			if entry.ebr.Size <= 0 {
+				continue
+			}
+			logical := defragLogical{
+				oldPos: entry.pos,
+				newPos: newExtStart + offset,
+				ebr:    entry.ebr,
+			}
+			if entry.pos-plan.extended.Start != offset || logical.newPos != entry.pos {
+				plan.Moves = append(plan.Moves, DefragMove{
+					Name:     strings.TrimRight(string(entry.ebr.Name[:]), " \x00"),
+					Type:     string(PARTITION_LOGIC),
+					OldStart: entry.pos,
+					NewStart: logical.newPos,
+					Size:     entry.ebr.Size,
+				})
+			}
+			plan.logicals = append(plan.logicals, logical)
+			offset += entry.ebr.Size
+		}
+	}
+
+	return plan, nil
+}
+
+// DefragDisk vuelve a calcular la propuesta y mueve las particiones y sus datos.
+// No se permite si alguna partición del disco está montada.
+func DefragDisk(diskPath string) (*DiskDefragPlan, error) {
+	for _, mp := range utils.MountedPartitions {
+		if mp.UsesDisk(diskPath) {
+			return nil, fmt.Errorf("no se puede desfragmentar el disco: la partición '%s' está montada con ID %s", mp.PartitionName, mp.ID)
+		}
+	}
+
+	plan, err := PlanDiskDefrag(diskPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Moves) == 0 {
+		return plan, nil
+	}
+
+	file, err := os.OpenFile(diskPath, os.O_RDWR, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	// 1. Compactar las lógicas dentro de la extendida, todavía en su lugar original.
+	// Los EBRs se escriben ya con las posiciones que tendrán al mover la extendida.
+	if plan.extended != nil {
+		if err := compactLogicals(file, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. Mover primarias y extendida en orden de posición; siempre se mueven hacia
+	// el inicio, así que una partición nunca pisa datos que falten por mover
+	moves := make([]DefragMove, 0, len(plan.Moves))
+	for _, move := range plan.Moves {
+		if move.Type != string(PARTITION_LOGIC) {
+			moves = append(moves, move)
+		}
+	}
+	sort.Slice(moves, func(a, b int) bool { return moves[a].OldStart < moves[b].OldStart })
+
+	oldEnd := int64(binary.Size(MBR{}))
+	newEnd := oldEnd
+	for _, p := range plan.mbr.MbrPartitions {
+		if p.Size > 0 && p.Start+p.Size > newEnd {
+			newEnd = p.Start + p.Size
+		}
+	}
+	for _, move := range moves {
+		fmt.Printf("Debug: Moviendo '%s' de %d a %d (%d bytes)\n", move.Name, move.OldStart, move.NewStart, move.Size)
+		if err := moveDiskRegion(file, move.OldStart, move.NewStart, move.Size); err != nil {
+			return nil, fmt.Errorf("error moviendo la partición '%s': %v", move.Name, err)
+		}
+		if move.OldStart+move.Size > oldEnd {
+			oldEnd = move.OldStart + move.Size
+		}
+	}
+
+	// 3. Lo que quedó detrás de la última partición ahora es espacio libre
+	if oldEnd > newEnd {
+		if err := zeroDiskRegion(file, newEnd, oldEnd-newEnd); err != nil {
+			return nil, fmt.Errorf("error limpiando el espacio liberado: %v", err)
+		}
+	}
+
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor para MBR: %v", err)
+	}
+	if err := binary.Write(file, binary.LittleEndian, plan.mbr); err != nil {
+		return nil, fmt.Errorf("error escribiendo MBR: %v", err)
+	}
+
+	fmt.Printf("Debug: Disco %s desfragmentado con %d movimientos\n", diskPath, len(plan.Moves))
+	return plan, nil
+}
+
+// compactLogicals junta las lógicas al inicio de la extendida y reescribe sus EBRs
+func compactLogicals(file *os.File, plan *DiskDefragPlan) error {
+	ext := plan.extended
+	newExtStart := ext.Start
+	for _, p := range plan.mbr.MbrPartitions {
+		if p.Type == PARTITION_EXTENDED && p.Size > 0 {
+			newExtStart = p.Start
+		}
+	}
+
+	// Sin lógicas solo queda el primer EBR vacío que marca el fin de la cadena
+	if len(plan.logicals) == 0 {
+		head := NewEBR()
+		head.Start = newExtStart
+		if _, err := file.Seek(ext.Start, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor para EBR: %v", err)
+		}
+		if err := binary.Write(file, binary.LittleEndian, head); err != nil {
+			return fmt.Errorf("error escribiendo EBR: %v", err)
+		}
+		return nil
+	}
+
+	cursor := ext.Start
+	oldEnd := ext.Start
+	for i, logical := range plan.logicals {
+		// Posición dentro de la extendida antes de moverla
+		target := logical.newPos - newExtStart + ext.Start
+		if target != logical.oldPos {
+			if err := moveDiskRegion(file, logical.oldPos, target, logical.ebr.Size); err != nil {
+				return fmt.Errorf("error moviendo la partición lógica '%s': %v",
+					strings.TrimRight(string(logical.ebr.Name[:]), " \x00"), err)
+			}
+		}
+
+		logical.ebr.Start = logical.newPos
+		logical.ebr.Next = -1
+		if i+1 < len(plan.logicals) {
+			logical.ebr.Next = plan.logicals[i+1].newPos
+		}
+		if _, err := file.Seek(target, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor para EBR: %v", err)
+		}
+		if err := binary.Write(file, binary.LittleEndian, logical.ebr); err != nil {
+			return fmt.Errorf("error escribiendo EBR: %v", err)
+		}
+
+		cursor = target + logical.ebr.Size
+		if logical.oldPos+logical.ebr.Size > oldEnd {
+			oldEnd = logical.oldPos + logical.ebr.Size
+		}
+	}
+
+	// Lo que ocupaban las lógicas movidas queda libre
+	if oldEnd > cursor {
+		if err := zeroDiskRegion(file, cursor, oldEnd-cursor); err != nil {
+			return fmt.Errorf("error limpiando el espacio liberado en la extendida: %v", err)
+		}
+	}
+	return nil
+}
+
+// moveDiskRegion copia size bytes de from a to por bloques. Solo mueve hacia el inicio
+// del disco (to <= from), así que copiar de adelante hacia atrás es seguro aunque se solapen.
+func moveDiskRegion(file *os.File, from, to, size int64) error {
+	if to > from {
+		return fmt.Errorf("solo se pueden mover datos hacia el inicio del disco")
+	}
+
+	buffer := make([]byte, CLONE_CHUNK)
+	for offset := int64(0); offset < size; offset += CLONE_CHUNK {
+		chunk := int64(CLONE_CHUNK)
+		if size-offset < chunk {
+			chunk = size - offset
+		}
+		if _, err := file.ReadAt(buffer[:chunk], from+offset); err != nil {
+			return err
+		}
+		if _, err := file.WriteAt(buffer[:chunk], to+offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// largestSpace retorna el tamaño del hueco más grande
+func largestSpace(spaces []Space) int64 {
+	var largest int64
+	for _, space := range spaces {
+		if space.size > largest {
+			largest = space.size
+		}
+	}
+	return largest
+}
@@ -1,6 +1,7 @@
 package DiskManager
 
 import (
+	"MIA_P1/backend/utils"
 	"sync"
 	"time"
 )
@@ -23,9 +24,11 @@ var (
 // RegisterDisk registra un nuevo disco en la lista de discos
 func RegisterDisk(disk DiskInfo) {
 	diskMutex.Lock()
-	defer diskMutex.Unlock()
 	disk.CreatedAt = time.Now()
 	disks[disk.Path] = disk
+	diskMutex.Unlock()
+
+	saveStateOrWarn()
 }
 
 // GetAllDisks retorna todos los discos registrados
@@ -56,15 +59,45 @@ func DiskExists(path string) bool {
 	return exists
 }
 
-// RemoveDisk elimina un disco del registro en memoria
+// RemoveDisk elimina un disco del registro y las particiones montadas que tenía
 func RemoveDisk(path string) bool {
 	diskMutex.Lock()
-	defer diskMutex.Unlock()
-
 	_, exists := disks[path]
 	if exists {
 		delete(disks, path)
-		return true
 	}
-	return false
+	diskMutex.Unlock()
+
+	// Las particiones montadas de un disco eliminado, y los volúmenes con alguna parte en
+	// él, ya no son válidos; si el disco era el espejo de una partición, esa sigue
+	// montada pero queda degradada
+	remaining := utils.MountedPartitions[:0]
+	for _, mp := range utils.MountedPartitions {
+		if mp.MirrorPath == path {
+			mp.MirrorStatus = MIRROR_DEGRADED
+		} else if mp.UsesDisk(path) {
+			continue
+		}
+		remaining = append(remaining, mp)
+	}
+	utils.MountedPartitions = remaining
+
+	saveStateOrWarn()
+	return exists
+}
+
+// updateDiskSize actualiza el tamaño registrado de un disco conservando el resto de datos
+func updateDiskSize(path string, size int, unit string) {
+	diskMutex.Lock()
+	disk, exists := disks[path]
+	if exists {
+		disk.Size = size
+		disk.Unit = unit
+		disks[path] = disk
+	}
+	diskMutex.Unlock()
+
+	if exists {
+		saveStateOrWarn()
+	}
 }
@@ -0,0 +1,190 @@
+package DiskManager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Modos de bloqueo de un disco
+const (
+	DISK_LOCK_SHARED    = false // lecturas: cat, reportes, consultas
+	DISK_LOCK_EXCLUSIVE = true  // modificaciones: mkfile, mkdir, mkfs, fdisk...
+)
+
+// DISK_LOCK_TIMEOUT es cuánto se espera a que otro proceso libere el archivo .mia.
+// Es variable para que las pruebas puedan acortarla.
+var DISK_LOCK_TIMEOUT = 5 * time.Second
+
+// Registro de candados por disco dentro del servidor
+var (
+	diskLocks     = make(map[string]*sync.RWMutex)
+	diskLocksLock = &sync.Mutex{}
+)
+
+// diskLockKey normaliza la ruta para que un mismo disco use siempre el mismo candado
+func diskLockKey(diskPath string) string {
+	key := filepath.Clean(diskPath)
+	if abs, err := filepath.Abs(key); err == nil {
+		key = abs
+	}
+	return key
+}
+
+// getDiskLock retorna el candado del disco, creándolo la primera vez
+func getDiskLock(diskPath string) *sync.RWMutex {
+	key := diskLockKey(diskPath)
+
+	diskLocksLock.Lock()
+	defer diskLocksLock.Unlock()
+
+	lock, exists := diskLocks[key]
+	if !exists {
+		lock = &sync.RWMutex{}
+		diskLocks[key] = lock
+	}
+	return lock
+}
+
+// LockDisk bloquea un disco para lectura (compartido) o escritura (exclusivo).
+// Además del candado del servidor toma un flock advisory sobre el archivo .mia para
+// que otro proceso no lo modifique al mismo tiempo. Retorna la función que libera ambos.
+func LockDisk(diskPath string, exclusive bool) (func(), error) {
+	lock := getDiskLock(diskPath)
+	if exclusive {
+		lock.Lock()
+	} else {
+		lock.RLock()
+	}
+	unlockMemory := func() {
+		if exclusive {
+			lock.Unlock()
+		} else {
+			lock.RUnlock()
+		}
+	}
+
+	// Un disco que todavía no existe (mkdisk) solo usa el candado del servidor
+	file, err := os.OpenFile(diskPath, os.O_RDONLY, 0666)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return unlockMemory, nil
+		}
+		unlockMemory()
+		return nil, fmt.Errorf("error abriendo disco para bloquearlo: %v", err)
+	}
+
+	deadline := time.Now().Add(DISK_LOCK_TIMEOUT)
+	for {
+		locked, err := tryFlock(file, exclusive)
+		if err != nil {
+			file.Close()
+			unlockMemory()
+			return nil, fmt.Errorf("error bloqueando disco: %v", err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			unlockMemory()
+			return nil, fmt.Errorf("el disco %s está siendo usado por otro proceso", diskPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return func() {
+		unlockFlock(file)
+		file.Close()
+		unlockMemory()
+	}, nil
+}
+
+// LockDiskPair bloquea el disco origen para lectura y el destino para escritura.
+// Los candados se toman en orden de ruta para que dos copias cruzadas no se esperen
+// entre sí; si origen y destino son el mismo disco se toma un solo candado exclusivo.
+func LockDiskPair(srcPath, destPath string) (func(), error) {
+	return lockDisksInOrder(srcPath, DISK_LOCK_SHARED, destPath, DISK_LOCK_EXCLUSIVE)
+}
+
+// LockMirrorPair bloquea en exclusivo el disco de una partición y el de su espejo
+func LockMirrorPair(diskPath, mirrorPath string) (func(), error) {
+	return lockDisksInOrder(diskPath, DISK_LOCK_EXCLUSIVE, mirrorPath, DISK_LOCK_EXCLUSIVE)
+}
+
+// lockDisksInOrder bloquea dos discos en orden de ruta con el modo de cada uno
+func lockDisksInOrder(aPath string, aMode bool, bPath string, bMode bool) (func(), error) {
+	if diskLockKey(aPath) == diskLockKey(bPath) {
+		return LockDisk(aPath, aMode || bMode)
+	}
+
+	firstPath, firstMode := aPath, aMode
+	secondPath, secondMode := bPath, bMode
+	if diskLockKey(bPath) < diskLockKey(aPath) {
+		firstPath, firstMode, secondPath, secondMode = secondPath, secondMode, firstPath, firstMode
+	}
+
+	unlockFirst, err := LockDisk(firstPath, firstMode)
+	if err != nil {
+		return nil, err
+	}
+	unlockSecond, err := LockDisk(secondPath, secondMode)
+	if err != nil {
+		unlockFirst()
+		return nil, err
+	}
+
+	return func() {
+		unlockSecond()
+		unlockFirst()
+	}, nil
+}
+
+// LockDisks bloquea varios discos con el mismo modo, en orden de ruta y una vez por disco
+func LockDisks(paths []string, exclusive bool) (func(), error) {
+	unique := make(map[string]string)
+	for _, path := range paths {
+		unique[diskLockKey(path)] = path
+	}
+	keys := make([]string, 0, len(unique))
+	for key := range unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var unlocks []func()
+	release := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, key := range keys {
+		unlock, err := LockDisk(unique[key], exclusive)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return release, nil
+}
+
+// LockMountedPartition bloquea el disco que contiene la partición montada con ese ID.
+// Si la partición tiene espejo se bloquean ambos discos y las escrituras se replican;
+// si es un volumen se bloquean los discos de todas sus partes.
+func LockMountedPartition(id string, exclusive bool) (func(), error) {
+	mp, err := FindMountedPartitionById(id)
+	if err != nil {
+		return nil, err
+	}
+	if mp.MirrorPath != "" {
+		return lockMirroredPartition(mp, exclusive)
+	}
+	if mp.PartitionType == PARTITION_VOLUME {
+		return lockVolume(mp, exclusive)
+	}
+	return LockDisk(mp.DiskPath, exclusive)
+}
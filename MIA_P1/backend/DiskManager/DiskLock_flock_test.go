@@ -0,0 +1,72 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package DiskManager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockDiskOtherProcess(t *testing.T) {
+	defer func(timeout time.Duration) { DISK_LOCK_TIMEOUT = timeout }(DISK_LOCK_TIMEOUT)
+	DISK_LOCK_TIMEOUT = 200 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		held      bool // modo del flock que tiene el otro proceso
+		requested bool
+		wantErr   bool
+	}{
+		{"lectura con lectura ajena", DISK_LOCK_SHARED, DISK_LOCK_SHARED, false},
+		{"lectura con escritura ajena", DISK_LOCK_EXCLUSIVE, DISK_LOCK_SHARED, true},
+		{"escritura con lectura ajena", DISK_LOCK_SHARED, DISK_LOCK_EXCLUSIVE, true},
+		{"escritura con escritura ajena", DISK_LOCK_EXCLUSIVE, DISK_LOCK_EXCLUSIVE, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := newTestDisk(t, t.TempDir(), "disco.mia")
+
+			// Otra apertura del archivo tiene su propio flock, como si fuera otro proceso
+			other, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer other.Close()
+			if locked, err := tryFlock(other, tt.held); !locked || err != nil {
+				t.Fatalf("no se pudo tomar el flock ajeno: %v", err)
+			}
+
+			start := time.Now()
+			unlock, err := LockDisk(path, tt.requested)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("LockDisk: %v", err)
+				}
+				unlock()
+				return
+			}
+			if err == nil {
+				unlock()
+				t.Fatalf("LockDisk no falló con el disco tomado por otro proceso")
+			}
+			if !strings.Contains(err.Error(), "otro proceso") {
+				t.Errorf("error inesperado: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed < DISK_LOCK_TIMEOUT {
+				t.Errorf("LockDisk falló a los %v, antes del tiempo de espera", elapsed)
+			}
+
+			// El candado del servidor se liberó al fallar
+			unlockFlock(other)
+			unlock, err = LockDisk(filepath.Clean(path), DISK_LOCK_EXCLUSIVE)
+			if err != nil {
+				t.Fatalf("LockDisk después de liberar el flock ajeno: %v", err)
+			}
+			unlock()
+		})
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package DiskManager
+
+import "os"
+
+// En plataformas sin flock solo se usa el candado dentro del servidor
+func tryFlock(file *os.File, exclusive bool) (bool, error) {
+	return true, nil
+}
+
+func unlockFlock(file *os.File) {}
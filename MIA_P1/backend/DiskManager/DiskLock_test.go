@@ -0,0 +1,283 @@
+package DiskManager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// lockWait es cuánto se espera para decidir que un bloqueo quedó esperando a otro
+const lockWait = 100 * time.Millisecond
+
+// newTestDisk crea en el directorio temporal de la prueba un archivo que hace de disco
+func newTestDisk(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, make([]byte, 1024), 0666); err != nil {
+		t.Fatalf("error creando disco de prueba: %v", err)
+	}
+	return path
+}
+
+// checkBlocks toma first y luego second en otra goroutine. Si wantBlock, second debe
+// esperar hasta que se libere first; si no, debe entrar mientras first sigue tomado.
+func checkBlocks(t *testing.T, first, second func() (func(), error), wantBlock bool) {
+	t.Helper()
+	unlockFirst, err := first()
+	if err != nil {
+		t.Fatalf("primer bloqueo: %v", err)
+	}
+
+	acquired := make(chan func(), 1)
+	go func() {
+		unlock, err := second()
+		if err != nil {
+			t.Errorf("segundo bloqueo: %v", err)
+			unlock = func() {}
+		}
+		acquired <- unlock
+	}()
+
+	select {
+	case unlockSecond := <-acquired:
+		if wantBlock {
+			t.Errorf("el segundo bloqueo no esperó al primero")
+		}
+		unlockSecond()
+		unlockFirst()
+		return
+	case <-time.After(lockWait):
+		if !wantBlock {
+			t.Errorf("el segundo bloqueo esperó al primero")
+		}
+	}
+
+	unlockFirst()
+	select {
+	case unlockSecond := <-acquired:
+		unlockSecond()
+	case <-time.After(time.Second):
+		t.Fatalf("el segundo bloqueo no avanzó al liberar el primero")
+	}
+}
+
+func TestLockDisk(t *testing.T) {
+	tests := []struct {
+		name          string
+		first, second bool
+		secondPath    func(t *testing.T, path string) string // ruta que usa el segundo bloqueo
+		missing       bool                                   // el disco todavía no existe (mkdisk)
+		wantBlock     bool
+	}{
+		{name: "dos lecturas", first: DISK_LOCK_SHARED, second: DISK_LOCK_SHARED},
+		{name: "lectura y escritura", first: DISK_LOCK_SHARED, second: DISK_LOCK_EXCLUSIVE, wantBlock: true},
+		{name: "escritura y lectura", first: DISK_LOCK_EXCLUSIVE, second: DISK_LOCK_SHARED, wantBlock: true},
+		{name: "dos escrituras", first: DISK_LOCK_EXCLUSIVE, second: DISK_LOCK_EXCLUSIVE, wantBlock: true},
+		{
+			name:  "misma ruta escrita de otra forma",
+			first: DISK_LOCK_EXCLUSIVE, second: DISK_LOCK_EXCLUSIVE,
+			secondPath: func(t *testing.T, path string) string {
+				return filepath.Join(filepath.Dir(path), "otro", "..", ".", filepath.Base(path))
+			},
+			wantBlock: true,
+		},
+		{
+			name:  "otro disco",
+			first: DISK_LOCK_EXCLUSIVE, second: DISK_LOCK_EXCLUSIVE,
+			secondPath: func(t *testing.T, path string) string {
+				return newTestDisk(t, filepath.Dir(path), "otro.mia")
+			},
+		},
+		{name: "disco inexistente", first: DISK_LOCK_EXCLUSIVE, second: DISK_LOCK_EXCLUSIVE, missing: true, wantBlock: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "disco.mia")
+			if !tt.missing {
+				newTestDisk(t, filepath.Dir(path), "disco.mia")
+			}
+			second := path
+			if tt.secondPath != nil {
+				second = tt.secondPath(t, path)
+			}
+
+			checkBlocks(t,
+				func() (func(), error) { return LockDisk(path, tt.first) },
+				func() (func(), error) { return LockDisk(second, tt.second) },
+				tt.wantBlock)
+		})
+	}
+}
+
+func TestLockDiskPair(t *testing.T) {
+	tests := []struct {
+		name      string
+		src, dest string // discos del par: "a" o "b"
+		other     string // disco que bloquea después otra operación
+		mode      bool
+		wantBlock bool
+	}{
+		{"leer el origen", "a", "b", "a", DISK_LOCK_SHARED, false},
+		{"escribir el origen", "a", "b", "a", DISK_LOCK_EXCLUSIVE, true},
+		{"leer el destino", "a", "b", "b", DISK_LOCK_SHARED, true},
+		{"origen mayor que destino", "b", "a", "b", DISK_LOCK_SHARED, false},
+		{"mismo disco", "a", "a", "a", DISK_LOCK_SHARED, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			disks := map[string]string{
+				"a": newTestDisk(t, dir, "a.mia"),
+				"b": newTestDisk(t, dir, "b.mia"),
+			}
+			checkBlocks(t,
+				func() (func(), error) { return LockDiskPair(disks[tt.src], disks[tt.dest]) },
+				func() (func(), error) { return LockDisk(disks[tt.other], tt.mode) },
+				tt.wantBlock)
+		})
+	}
+}
+
+// Dos copias cruzadas (a→b y b→a) toman los candados en el mismo orden y no se bloquean
+// mutuamente
+func TestLockDiskPairCrossed(t *testing.T) {
+	dir := t.TempDir()
+	a, b := newTestDisk(t, dir, "a.mia"), newTestDisk(t, dir, "b.mia")
+
+	done := make(chan error)
+	for i := 0; i < 8; i++ {
+		src, dest := a, b
+		if i%2 == 1 {
+			src, dest = b, a
+		}
+		go func() {
+			for j := 0; j < 20; j++ {
+				unlock, err := LockDiskPair(src, dest)
+				if err != nil {
+					done <- err
+					return
+				}
+				unlock()
+			}
+			done <- nil
+		}()
+	}
+
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < 8; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("LockDiskPair: %v", err)
+			}
+		case <-timeout:
+			t.Fatalf("las copias cruzadas quedaron esperándose entre sí")
+		}
+	}
+}
+
+func TestLockMirrorPair(t *testing.T) {
+	tests := []struct {
+		name            string
+		primary, mirror string // discos del par: "a", "b" o "c"
+		other           string // disco que bloquea después otra operación
+		mode, wantBlock bool
+	}{
+		{"leer la principal", "a", "b", "a", DISK_LOCK_SHARED, true},
+		{"leer el espejo", "a", "b", "b", DISK_LOCK_SHARED, true},
+		{"espejo antes en orden", "b", "a", "a", DISK_LOCK_SHARED, true},
+		{"otro disco", "a", "b", "c", DISK_LOCK_EXCLUSIVE, false},
+		{"mismo disco", "a", "a", "a", DISK_LOCK_SHARED, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			disks := map[string]string{
+				"a": newTestDisk(t, dir, "a.mia"),
+				"b": newTestDisk(t, dir, "b.mia"),
+				"c": newTestDisk(t, dir, "c.mia"),
+			}
+			checkBlocks(t,
+				func() (func(), error) { return LockMirrorPair(disks[tt.primary], disks[tt.mirror]) },
+				func() (func(), error) { return LockDisk(disks[tt.other], tt.mode) },
+				tt.wantBlock)
+		})
+	}
+}
+
+func TestLockDisks(t *testing.T) {
+	tests := []struct {
+		name      string
+		disks     []string // "a", "b", "c", o "a2" para la ruta de a escrita de otra forma
+		mode      bool
+		other     string
+		otherMode bool
+		wantBlock bool
+	}{
+		{"escritura", []string{"a", "b"}, DISK_LOCK_EXCLUSIVE, "b", DISK_LOCK_SHARED, true},
+		{"lecturas", []string{"a", "b"}, DISK_LOCK_SHARED, "a", DISK_LOCK_SHARED, false},
+		{"lectura y escritura", []string{"b", "a"}, DISK_LOCK_SHARED, "b", DISK_LOCK_EXCLUSIVE, true},
+		{"disco repetido", []string{"a", "b", "a2"}, DISK_LOCK_EXCLUSIVE, "a", DISK_LOCK_SHARED, true},
+		{"otro disco", []string{"a", "b"}, DISK_LOCK_EXCLUSIVE, "c", DISK_LOCK_EXCLUSIVE, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			disks := map[string]string{
+				"a":  newTestDisk(t, dir, "a.mia"),
+				"a2": filepath.Join(dir, ".", "a.mia"),
+				"b":  newTestDisk(t, dir, "b.mia"),
+				"c":  newTestDisk(t, dir, "c.mia"),
+			}
+			var paths []string
+			for _, disk := range tt.disks {
+				paths = append(paths, disks[disk])
+			}
+			checkBlocks(t,
+				func() (func(), error) { return LockDisks(paths, tt.mode) },
+				func() (func(), error) { return LockDisk(disks[tt.other], tt.otherMode) },
+				tt.wantBlock)
+		})
+	}
+}
+
+// Dos volúmenes con las mismas partes en distinto orden toman los candados en el mismo
+// orden y no se bloquean mutuamente
+func TestLockDisksCrossed(t *testing.T) {
+	dir := t.TempDir()
+	a, b, c := newTestDisk(t, dir, "a.mia"), newTestDisk(t, dir, "b.mia"), newTestDisk(t, dir, "c.mia")
+	orders := [][]string{{a, b, c}, {c, b, a}, {b, c, a}}
+
+	done := make(chan error)
+	for i := 0; i < 9; i++ {
+		paths := orders[i%len(orders)]
+		go func() {
+			for j := 0; j < 20; j++ {
+				unlock, err := LockDisks(paths, DISK_LOCK_EXCLUSIVE)
+				if err != nil {
+					done <- err
+					return
+				}
+				unlock()
+			}
+			done <- nil
+		}()
+	}
+
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < 9; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("LockDisks: %v", err)
+			}
+		case <-timeout:
+			t.Fatalf("los volúmenes quedaron esperándose entre sí")
+		}
+	}
+}
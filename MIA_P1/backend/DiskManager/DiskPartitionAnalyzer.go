@@ -42,6 +42,13 @@ func GetAllPartitionsInfo() ([]BasicPartitionInfo, error) {
 
 // GetDiskPartitionsInfo obtiene información básica de las particiones de un disco
 func GetDiskPartitionsInfo(diskPath string) ([]BasicPartitionInfo, error) {
+	// Lectura: candado compartido del disco
+	unlock, err := LockDisk(diskPath, DISK_LOCK_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// Abrir el archivo del disco
 	file, err := os.OpenFile(diskPath, os.O_RDONLY, 0666)
 	if err != nil {
@@ -53,6 +53,13 @@ func AnalyzeAllDisks() ([]DiskAnalysis, error) {
 
 // AnalyzeDiskStructure analiza la estructura completa de un disco sin generar un reporte gráfico
 func AnalyzeDiskStructure(diskPath string) (*DiskAnalysis, error) {
+	// Lectura: candado compartido del disco
+	unlock, err := LockDisk(diskPath, DISK_LOCK_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// 1. Abrir el disco
 	file, err := os.OpenFile(diskPath, os.O_RDONLY, 0666)
 	if err != nil {
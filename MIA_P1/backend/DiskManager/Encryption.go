@@ -0,0 +1,413 @@
+package DiskManager
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Formato de una partición cifrada: una cabecera al inicio de la zona de datos y después
+// sectores de ENCRYPTED_SECTOR_SIZE bytes cifrados con AES-GCM. Cada sector se guarda como
+// nonce + texto cifrado + etiqueta, y su número va como dato autenticado para que no se
+// pueda mover un sector a otra posición sin que se detecte.
+const (
+	ENCRYPTION_MAGIC       = "MIACRYPT"
+	ENCRYPTION_HEADER_SIZE = 512
+	ENCRYPTED_SECTOR_SIZE  = 512
+	ENCRYPTION_KDF_ROUNDS  = 100000
+	ENCRYPTION_KEY_SIZE    = 32
+	encryptionNonceSize    = 12
+	encryptionTagSize      = 16
+	encryptedSectorDisk    = encryptionNonceSize + ENCRYPTED_SECTOR_SIZE + encryptionTagSize
+)
+
+// encryptionCheck se cifra en la cabecera para reconocer una contraseña incorrecta
+var encryptionCheck = []byte("MIA-VERIFICACION")
+
+// encryptionHeader es la cabecera de una partición cifrada
+type encryptionHeader struct {
+	Magic      [8]byte
+	Rounds     int32 // iteraciones de PBKDF2-SHA256
+	Salt       [16]byte
+	CheckNonce [encryptionNonceSize]byte
+	Check      [32]byte // encryptionCheck cifrado con la clave, con su etiqueta
+}
+
+// Claves de las particiones desbloqueadas con mount -pass. Solo viven en memoria:
+// no se guardan en el estado, así que después de reiniciar hay que volver a montar.
+var (
+	partitionKeys     = make(map[string][]byte)
+	partitionKeysLock = &sync.Mutex{}
+)
+
+func partitionKeyName(diskPath, name string) string {
+	return diskLockKey(diskPath) + "|" + name
+}
+
+func getPartitionKey(diskPath, name string) []byte {
+	partitionKeysLock.Lock()
+	defer partitionKeysLock.Unlock()
+	return partitionKeys[partitionKeyName(diskPath, name)]
+}
+
+func setPartitionKey(diskPath, name string, key []byte) {
+	partitionKeysLock.Lock()
+	defer partitionKeysLock.Unlock()
+	partitionKeys[partitionKeyName(diskPath, name)] = key
+}
+
+// ForgetPartitionKey descarta la clave de una partición desbloqueada
+func ForgetPartitionKey(diskPath, name string) {
+	partitionKeysLock.Lock()
+	defer partitionKeysLock.Unlock()
+	delete(partitionKeys, partitionKeyName(diskPath, name))
+}
+
+// readEncryptionHeader lee la cabecera en start; retorna nil si la zona no está cifrada
+func readEncryptionHeader(file io.ReaderAt, start int64) (*encryptionHeader, error) {
+	data := make([]byte, binary.Size(encryptionHeader{}))
+	if _, err := file.ReadAt(data, start); err != nil {
+		return nil, fmt.Errorf("error leyendo la cabecera de cifrado: %v", err)
+	}
+	header := &encryptionHeader{}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, header); err != nil {
+		return nil, fmt.Errorf("error leyendo la cabecera de cifrado: %v", err)
+	}
+	if string(header.Magic[:]) != ENCRYPTION_MAGIC {
+		return nil, nil
+	}
+	return header, nil
+}
+
+func newPartitionCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// unlockEncryptionHeader deriva la clave de la contraseña y verifica que sea la correcta
+func unlockEncryptionHeader(header *encryptionHeader, passphrase string) ([]byte, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, header.Salt[:], int(header.Rounds), ENCRYPTION_KEY_SIZE)
+	if err != nil {
+		return nil, fmt.Errorf("error derivando la clave: %v", err)
+	}
+	aead, err := newPartitionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	check, err := aead.Open(nil, header.CheckNonce[:], header.Check[:], []byte(ENCRYPTION_MAGIC))
+	if err != nil || !bytes.Equal(check, encryptionCheck) {
+		return nil, fmt.Errorf("contraseña incorrecta")
+	}
+	return key, nil
+}
+
+// EncryptedPartition descifra y cifra por sectores la zona de datos de una partición
+// cifrada. El código EXT2 la usa como cualquier PartitionFile, desde el byte 0.
+type EncryptedPartition struct {
+	base  PartitionFile
+	start int64 // inicio del primer sector cifrado en base
+	aead  cipher.AEAD
+	size  int64 // tamaño descifrado
+	pos   int64
+}
+
+// encryptedDataSize retorna cuántos bytes descifrados caben en una zona de regionSize bytes
+func encryptedDataSize(regionSize int64) int64 {
+	if regionSize <= ENCRYPTION_HEADER_SIZE {
+		return 0
+	}
+	return (regionSize - ENCRYPTION_HEADER_SIZE) / encryptedSectorDisk * ENCRYPTED_SECTOR_SIZE
+}
+
+func newEncryptedPartition(base PartitionFile, regionStart, regionSize int64, key []byte) (*EncryptedPartition, error) {
+	aead, err := newPartitionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedPartition{
+		base:  base,
+		start: regionStart + ENCRYPTION_HEADER_SIZE,
+		aead:  aead,
+		size:  encryptedDataSize(regionSize),
+	}, nil
+}
+
+func sectorAAD(sector int64) []byte {
+	aad := make([]byte, 8)
+	binary.LittleEndian.PutUint64(aad, uint64(sector))
+	return aad
+}
+
+func (e *EncryptedPartition) readSector(sector int64) ([]byte, error) {
+	raw := make([]byte, encryptedSectorDisk)
+	if _, err := e.base.ReadAt(raw, e.start+sector*encryptedSectorDisk); err != nil {
+		return nil, err
+	}
+	plain, err := e.aead.Open(nil, raw[:encryptionNonceSize], raw[encryptionNonceSize:], sectorAAD(sector))
+	if err != nil {
+		return nil, fmt.Errorf("el sector cifrado %d no pasó la verificación de integridad", sector)
+	}
+	return plain, nil
+}
+
+func (e *EncryptedPartition) writeSector(sector int64, plain []byte) error {
+	raw := make([]byte, encryptionNonceSize, encryptedSectorDisk)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Errorf("error generando nonce: %v", err)
+	}
+	raw = e.aead.Seal(raw, raw[:encryptionNonceSize], plain, sectorAAD(sector))
+	_, err := e.base.WriteAt(raw, e.start+sector*encryptedSectorDisk)
+	return err
+}
+
+// access lee o escribe p en la posición off, sector por sector. Una escritura que no
+// cubre un sector completo lo lee, lo modifica y lo vuelve a cifrar.
+func (e *EncryptedPartition) access(p []byte, off int64, write bool) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("posición negativa en la partición cifrada: %d", off)
+	}
+
+	done := 0
+	for done < len(p) {
+		current := off + int64(done)
+		if current >= e.size {
+			if write {
+				return done, fmt.Errorf("escritura fuera de la partición cifrada (posición %d, tamaño %d)", current, e.size)
+			}
+			return done, io.EOF
+		}
+		sector := current / ENCRYPTED_SECTOR_SIZE
+		inSector := int(current % ENCRYPTED_SECTOR_SIZE)
+		chunk := len(p) - done
+		if chunk > ENCRYPTED_SECTOR_SIZE-inSector {
+			chunk = ENCRYPTED_SECTOR_SIZE - inSector
+		}
+
+		var plain []byte
+		if write && chunk == ENCRYPTED_SECTOR_SIZE {
+			plain = p[done : done+chunk]
+		} else {
+			var err error
+			if plain, err = e.readSector(sector); err != nil {
+				return done, err
+			}
+		}
+
+		if write {
+			copy(plain[inSector:], p[done:done+chunk])
+			if err := e.writeSector(sector, plain); err != nil {
+				return done, err
+			}
+		} else {
+			copy(p[done:done+chunk], plain[inSector:])
+		}
+		done += chunk
+	}
+	return done, nil
+}
+
+func (e *EncryptedPartition) ReadAt(p []byte, off int64) (int, error) {
+	return e.access(p, off, false)
+}
+
+func (e *EncryptedPartition) WriteAt(p []byte, off int64) (int, error) {
+	return e.access(p, off, true)
+}
+
+func (e *EncryptedPartition) Read(p []byte) (int, error) {
+	n, err := e.ReadAt(p, e.pos)
+	e.pos += int64(n)
+	return n, err
+}
+
+func (e *EncryptedPartition) Write(p []byte) (int, error) {
+	n, err := e.WriteAt(p, e.pos)
+	e.pos += int64(n)
+	return n, err
+}
+
+func (e *EncryptedPartition) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += e.pos
+	case io.SeekEnd:
+		offset += e.size
+	default:
+		return e.pos, fmt.Errorf("whence inválido: %d", whence)
+	}
+	if offset < 0 {
+		return e.pos, fmt.Errorf("posición negativa en la partición cifrada: %d", offset)
+	}
+	e.pos = offset
+	return e.pos, nil
+}
+
+func (e *EncryptedPartition) Sync() error {
+	return e.base.Sync()
+}
+
+func (e *EncryptedPartition) Close() error {
+	return e.base.Close()
+}
+
+// openEncryptedIfNeeded envuelve la zona de datos de una partición en un EncryptedPartition
+// si tiene cabecera de cifrado; si no, retorna el archivo tal cual
+func openEncryptedIfNeeded(file PartitionFile, diskPath, name string, start, size int64) (PartitionFile, int64, int64, error) {
+	header, err := readEncryptionHeader(file, start)
+	if err != nil || header == nil {
+		return file, start, size, nil
+	}
+	key := getPartitionKey(diskPath, name)
+	if key == nil {
+		return nil, 0, 0, fmt.Errorf("la partición '%s' está cifrada y no está desbloqueada, vuelva a montarla con -pass", name)
+	}
+	encrypted, err := newEncryptedPartition(file, start, size, key)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return encrypted, 0, encrypted.size, nil
+}
+
+// encryptRegion escribe una cabecera nueva con una sal aleatoria y llena todos los sectores
+// con ceros cifrados, así cualquier sector se puede leer y verificar. Retorna la clave.
+func encryptRegion(file PartitionFile, start, size int64, passphrase string) ([]byte, error) {
+	if encryptedDataSize(size) < SUPERBLOCK_SIZE {
+		return nil, fmt.Errorf("la partición es demasiado pequeña para cifrarla")
+	}
+
+	header := &encryptionHeader{Rounds: ENCRYPTION_KDF_ROUNDS}
+	copy(header.Magic[:], ENCRYPTION_MAGIC)
+	if _, err := rand.Read(header.Salt[:]); err != nil {
+		return nil, fmt.Errorf("error generando sal: %v", err)
+	}
+	if _, err := rand.Read(header.CheckNonce[:]); err != nil {
+		return nil, fmt.Errorf("error generando nonce: %v", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, header.Salt[:], ENCRYPTION_KDF_ROUNDS, ENCRYPTION_KEY_SIZE)
+	if err != nil {
+		return nil, fmt.Errorf("error derivando la clave: %v", err)
+	}
+	aead, err := newPartitionCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	copy(header.Check[:], aead.Seal(nil, header.CheckNonce[:], encryptionCheck, []byte(ENCRYPTION_MAGIC)))
+
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	headerData := make([]byte, ENCRYPTION_HEADER_SIZE)
+	copy(headerData, buf.Bytes())
+	if _, err := file.WriteAt(headerData, start); err != nil {
+		return nil, fmt.Errorf("error escribiendo la cabecera de cifrado: %v", err)
+	}
+
+	encrypted, err := newEncryptedPartition(file, start, size, key)
+	if err != nil {
+		return nil, err
+	}
+	zeros := make([]byte, ENCRYPTED_SECTOR_SIZE)
+	for sector := int64(0); sector < encrypted.size/ENCRYPTED_SECTOR_SIZE; sector++ {
+		if err := encrypted.writeSector(sector, zeros); err != nil {
+			return nil, fmt.Errorf("error inicializando la partición cifrada: %v", err)
+		}
+	}
+	return key, nil
+}
+
+// findPartitionRegion abre el disco y ubica la zona de datos de la partición name
+func findPartitionRegion(diskPath, name string, flag int) (*os.File, *Partition, error) {
+	pm, err := NewPartitionManager(diskPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.OpenFile(diskPath, flag, 0666)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	region, err := pm.findDataRegion(file, name)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, region, nil
+}
+
+// EncryptPartition cifra la partición name del disco con la contraseña. Lo que tuviera
+// la partición se pierde; si está montada queda desbloqueada con la clave nueva.
+func EncryptPartition(diskPath, name, passphrase string) error {
+	file, region, err := findPartitionRegion(diskPath, name, os.O_RDWR)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	key, err := encryptRegion(file, region.Start, region.Size, passphrase)
+	if err != nil {
+		return err
+	}
+	setPartitionKey(diskPath, name, key)
+	fmt.Printf("Debug: Partición '%s' de %s cifrada (%d bytes útiles)\n", name, diskPath, encryptedDataSize(region.Size))
+	return nil
+}
+
+// IsPartitionEncrypted indica si la partición name del disco tiene cabecera de cifrado
+func IsPartitionEncrypted(diskPath, name string) (bool, error) {
+	file, region, err := findPartitionRegion(diskPath, name, os.O_RDONLY)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	header, err := readEncryptionHeader(file, region.Start)
+	return header != nil, err
+}
+
+// UnlockPartition verifica la contraseña de una partición cifrada y guarda su clave en
+// memoria para que se pueda montar. Una contraseña incorrecta retorna un error.
+func UnlockPartition(diskPath, name, passphrase string) error {
+	file, region, err := findPartitionRegion(diskPath, name, os.O_RDONLY)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header, err := readEncryptionHeader(file, region.Start)
+	if err != nil {
+		return err
+	}
+	if header == nil {
+		return fmt.Errorf("la partición '%s' no está cifrada", name)
+	}
+	key, err := unlockEncryptionHeader(header, passphrase)
+	if err != nil {
+		return err
+	}
+	setPartitionKey(diskPath, name, key)
+	return nil
+}
+
+// requireUnlocked retorna un error si la partición está cifrada y no se desbloqueó
+func requireUnlocked(diskPath, name string) error {
+	encrypted, err := IsPartitionEncrypted(diskPath, name)
+	if err != nil || !encrypted {
+		return nil
+	}
+	if getPartitionKey(diskPath, name) == nil {
+		return fmt.Errorf("la partición '%s' está cifrada, use -pass para montarla", name)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package DiskManager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EXT2_BAD_BLOCKS_INODE es el inodo reservado dueño de los bloques dañados, como en ext2.
+	// Sus apuntadores directos y su bloque indirecto simple son la lista de bloques dañados.
+	EXT2_BAD_BLOCKS_INODE = 1
+	// MAX_BAD_BLOCKS es la capacidad de la lista: 12 directos más un bloque de apuntadores
+	MAX_BAD_BLOCKS = INDIRECT_BLOCK_INDEX + POINTERS_PER_BLOCK
+)
+
+// newBadBlocksInode arma el inodo de bloques dañados con la lista dada. Si la lista no
+// cabe en los apuntadores directos, el resto va en el bloque de apuntadores listBlock.
+func newBadBlocksInode(badBlocks []int32, listBlock int32, blockSize int32) (*Inode, *PointerBlock) {
+	inode := NewInode(0, 0, INODE_FILE)
+	inode.IPerm = [3]byte{0, 0, 0}
+	inode.ISize = int32(len(badBlocks)) * blockSize
+
+	var pointerBlock *PointerBlock
+	for i, blockNum := range badBlocks {
+		if i < INDIRECT_BLOCK_INDEX {
+			inode.IBlock[i] = blockNum
+			continue
+		}
+		if pointerBlock == nil {
+			pointerBlock = NewPointerBlock()
+			inode.IBlock[INDIRECT_BLOCK_INDEX] = listBlock
+		}
+		pointerBlock.BPointers[i-INDIRECT_BLOCK_INDEX] = blockNum
+	}
+	return inode, pointerBlock
+}
+
+// readBadBlocks lee la lista de bloques dañados del inodo reservado y el bloque de
+// apuntadores que la continúa (-1 si no tiene). Un sistema formateado antes de existir
+// la lista tiene ese inodo vacío y retorna una lista vacía.
+func readBadBlocks(file PartitionFile, startByte int64, sb *SuperBlock) ([]int32, int32, error) {
+	inodePos := startByte + int64(sb.SInodeStart) + EXT2_BAD_BLOCKS_INODE*int64(sb.SInodeSize)
+	if _, err := file.Seek(inodePos, 0); err != nil {
+		return nil, -1, fmt.Errorf("error posicionando cursor para el inodo de bloques dañados: %v", err)
+	}
+	inode, err := readInodeFromDisc(file)
+	if err != nil {
+		return nil, -1, fmt.Errorf("error leyendo el inodo de bloques dañados: %v", err)
+	}
+	if inode.IType != INODE_FILE {
+		return nil, -1, nil
+	}
+
+	var badBlocks []int32
+	for _, blockNum := range inode.IBlock[:INDIRECT_BLOCK_INDEX] {
+		if blockNum >= 0 && blockNum < sb.SBlocksCount {
+			badBlocks = append(badBlocks, blockNum)
+		}
+	}
+
+	listBlock := inode.IBlock[INDIRECT_BLOCK_INDEX]
+	if listBlock < 0 || listBlock >= sb.SBlocksCount {
+		return badBlocks, -1, nil
+	}
+	blockPos := startByte + int64(sb.SBlockStart) + int64(listBlock)*int64(sb.SBlockSize)
+	if _, err := file.Seek(blockPos, 0); err != nil {
+		return nil, -1, fmt.Errorf("error posicionando cursor para la lista de bloques dañados: %v", err)
+	}
+	pointerBlock, err := readPointerBlockFromDisc(file, int64(sb.SBlockSize))
+	if err != nil {
+		return nil, -1, fmt.Errorf("error leyendo la lista de bloques dañados: %v", err)
+	}
+	for _, blockNum := range pointerBlock.BPointers {
+		if blockNum >= 0 && blockNum < sb.SBlocksCount {
+			badBlocks = append(badBlocks, blockNum)
+		}
+	}
+	return badBlocks, listBlock, nil
+}
+
+// writeBadBlocks escribe el inodo de bloques dañados y, si hace falta, su bloque de apuntadores
+func writeBadBlocks(file PartitionFile, startByte int64, sb *SuperBlock, badBlocks []int32, listBlock int32) error {
+	inode, pointerBlock := newBadBlocksInode(badBlocks, listBlock, sb.SBlockSize)
+
+	inodePos := startByte + int64(sb.SInodeStart) + EXT2_BAD_BLOCKS_INODE*int64(sb.SInodeSize)
+	if _, err := file.Seek(inodePos, 0); err != nil {
+		return fmt.Errorf("error posicionando cursor para el inodo de bloques dañados: %v", err)
+	}
+	if err := writeInodeToDisc(file, inode); err != nil {
+		return fmt.Errorf("error escribiendo el inodo de bloques dañados: %v", err)
+	}
+
+	if pointerBlock == nil {
+		return nil
+	}
+	blockPos := startByte + int64(sb.SBlockStart) + int64(listBlock)*int64(sb.SBlockSize)
+	if _, err := file.Seek(blockPos, 0); err != nil {
+		return fmt.Errorf("error posicionando cursor para la lista de bloques dañados: %v", err)
+	}
+	if err := writePointerBlockToDisc(file, pointerBlock); err != nil {
+		return fmt.Errorf("error escribiendo la lista de bloques dañados: %v", err)
+	}
+	return nil
+}
+
+// MarkBadBlock agrega un bloque a la lista de bloques dañados de una partición formateada.
+// El bloque queda ocupado en el bitmap y ya no se asigna a ningún archivo. No se permite
+// marcar un bloque que esté en uso, porque sus datos quedarían en un bloque dañado.
+// Retorna la lista actualizada. En EXT3 la operación se registra en el journal.
+func MarkBadBlock(id string, blockNum int32) ([]int32, error) {
+	var list []int32
+	op := journalOp{operation: JOURNAL_BADBLOCK, content: strconv.Itoa(int(blockNum))}
+	err := journalOperation(id, op, func() error {
+		var err error
+		list, err = markBadBlock(id, blockNum)
+		return err
+	})
+	return list, err
+}
+
+func markBadBlock(id string, blockNum int32) ([]int32, error) {
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckWritable(id); err != nil {
+		return nil, err
+	}
+
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+	}
+	superblock, err := ReadSuperBlockFromDisc(file)
+	if err != nil || superblock.SMagic != EXT2_MAGIC {
+		return nil, fmt.Errorf("la partición %s no tiene un sistema de archivos EXT2", id)
+	}
+
+	if blockNum < 0 || blockNum >= superblock.SBlocksCount {
+		return nil, fmt.Errorf("el bloque %d está fuera de rango (0-%d)", blockNum, superblock.SBlocksCount-1)
+	}
+
+	badBlocks, listBlock, err := readBadBlocks(file, startByte, superblock)
+	if err != nil {
+		return nil, err
+	}
+	for _, bad := range badBlocks {
+		if bad == blockNum {
+			return nil, fmt.Errorf("el bloque %d ya está marcado como dañado", blockNum)
+		}
+	}
+	if len(badBlocks) >= MAX_BAD_BLOCKS {
+		return nil, fmt.Errorf("la lista de bloques dañados está llena (máximo %d)", MAX_BAD_BLOCKS)
+	}
+
+	blockBitmap, err := loadBlockBitmap(file, startByte, superblock)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los bloques reservados por mkfs nunca se asignan, así que se pueden listar aunque
+	// figuren ocupados; cualquier otro bloque ocupado pertenece a un archivo o carpeta
+	used := blockBitmap[blockNum/8]&(1<<(blockNum%8)) != 0
+	if used && blockNum >= EXT2_RESERVED_INODES {
+		return nil, fmt.Errorf("el bloque %d está en uso, no se puede marcar como dañado", blockNum)
+	}
+	if !used {
+		blockBitmap[blockNum/8] |= 1 << (blockNum % 8)
+		superblock.AllocateBlock()
+	}
+
+	// La lista no cabe en los apuntadores directos: reservar su bloque de apuntadores
+	if len(badBlocks) >= INDIRECT_BLOCK_INDEX && listBlock < 0 {
+		criticalBlocks := identifyCriticalBlocks(file, startByte, superblock)
+		criticalBlocks[blockNum] = true
+		free := findSafeBlockNum(blockBitmap, int(superblock.SBlocksCount), criticalBlocks)
+		if free < 0 {
+			return nil, fmt.Errorf("no hay bloques libres para ampliar la lista de bloques dañados")
+		}
+		listBlock = int32(free)
+		blockBitmap[free/8] |= 1 << (free % 8)
+		superblock.AllocateBlock()
+	}
+	badBlocks = append(badBlocks, blockNum)
+
+	if err := writeBadBlocks(file, startByte, superblock, badBlocks, listBlock); err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(startByte+int64(superblock.SBmBlockStart), 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor para bitmap de bloques: %v", err)
+	}
+	if _, err := file.Write(blockBitmap); err != nil {
+		return nil, fmt.Errorf("error escribiendo bitmap de bloques: %v", err)
+	}
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+	}
+	if err := writeSuperBlockToDisc(file, superblock); err != nil {
+		return nil, fmt.Errorf("error escribiendo superbloque: %v", err)
+	}
+
+	fmt.Printf("Debug: Bloque %d marcado como dañado en %s (%d en la lista)\n", blockNum, id, len(badBlocks))
+	return badBlocks, nil
+}
+
+// GetBadBlocks retorna la lista de bloques dañados de una partición montada
+func GetBadBlocks(id string) ([]int32, *SuperBlock, error) {
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDONLY)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return nil, nil, fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+	}
+	superblock, err := ReadSuperBlockFromDisc(file)
+	if err != nil || superblock.SMagic != EXT2_MAGIC {
+		return nil, nil, fmt.Errorf("la partición %s no tiene un sistema de archivos EXT2", id)
+	}
+
+	badBlocks, _, err := readBadBlocks(file, startByte, superblock)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(badBlocks, func(a, b int) bool { return badBlocks[a] < badBlocks[b] })
+	return badBlocks, superblock, nil
+}
+
+// BadBlocksReporter genera el reporte de texto con la lista de bloques dañados
+func BadBlocksReporter(id, path string) (bool, string) {
+	badBlocks, superblock, err := GetBadBlocks(id)
+	if err != nil {
+		return false, fmt.Sprintf("Error: %s", err)
+	}
+
+	var report strings.Builder
+	report.WriteString("===========================================================\n")
+	report.WriteString(fmt.Sprintf("           REPORTE DE BLOQUES DAÑADOS - PARTICIÓN %s\n", id))
+	report.WriteString("===========================================================\n\n")
+
+	report.WriteString(fmt.Sprintf("- Partición montada: %s\n", id))
+	report.WriteString(fmt.Sprintf("- Cantidad total de bloques: %d\n", superblock.SBlocksCount))
+	report.WriteString(fmt.Sprintf("- Inodo de bloques dañados: %d\n", EXT2_BAD_BLOCKS_INODE))
+	report.WriteString(fmt.Sprintf("- Bloques dañados: %d de %d posibles\n\n", len(badBlocks), MAX_BAD_BLOCKS))
+
+	report.WriteString("BLOQUE       POSICIÓN EN LA PARTICIÓN\n")
+	report.WriteString("-----------------------------------------------------------\n")
+	if len(badBlocks) == 0 {
+		report.WriteString("(sin bloques dañados)\n")
+	}
+	for _, blockNum := range badBlocks {
+		offset := int64(superblock.SBlockStart) + int64(blockNum)*int64(superblock.SBlockSize)
+		report.WriteString(fmt.Sprintf("%-12d %d - %d\n", blockNum, offset, offset+int64(superblock.SBlockSize)-1))
+	}
+
+	outputPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".txt"
+	if err := os.WriteFile(outputPath, []byte(report.String()), 0644); err != nil {
+		return false, fmt.Sprintf("Error al escribir el archivo de reporte: %s", err)
+	}
+	if err := OpenTextFile(outputPath); err != nil {
+		fmt.Printf("Advertencia: No se pudo abrir el reporte automáticamente: %v\n", err)
+	}
+	return true, fmt.Sprintf("Reporte de bloques dañados generado exitosamente en: %s", outputPath)
+}
@@ -7,10 +7,11 @@ import (
 
 // BitmapManager gestiona los bitmaps de inodos y bloques para el sistema de archivos EXT2
 type BitmapManager struct {
-	InodeBitmap []byte // Bitmap para inodos (1 bit por inodo)
-	BlockBitmap []byte // Bitmap para bloques (1 bit por bloque)
-	InodeCount  int    // Número total de inodos
-	BlockCount  int    // Número total de bloques
+	InodeBitmap []byte       // Bitmap para inodos (1 bit por inodo)
+	BlockBitmap []byte       // Bitmap para bloques (1 bit por bloque)
+	InodeCount  int          // Número total de inodos
+	BlockCount  int          // Número total de bloques
+	BadBlocks   map[int]bool // Bloques dañados: nunca se asignan ni se liberan
 }
 
 // NewBitmapManager crea una nueva instancia del gestor de bitmaps
@@ -25,6 +26,7 @@ func NewBitmapManager(inodeCount, blockCount int) *BitmapManager {
 		BlockBitmap: make([]byte, blockBitmapSize),
 		InodeCount:  inodeCount,
 		BlockCount:  blockCount,
+		BadBlocks:   make(map[int]bool),
 	}
 
 	// Inicializar todos los bits a 0 (libres)
@@ -107,6 +109,9 @@ func (bm *BitmapManager) FreeInode(inodeNum int) error {
 func (bm *BitmapManager) AllocateBlock() int {
 	// Buscar el primer bit libre (0)
 	for i := 0; i < bm.BlockCount; i++ {
+		if bm.BadBlocks[i] {
+			continue
+		}
 		isBusy, _ := bm.IsBitSet(bm.BlockBitmap, i)
 		if !isBusy {
 			// Marcar como ocupado
@@ -123,9 +128,26 @@ func (bm *BitmapManager) FreeBlock(blockNum int) error {
 		return fmt.Errorf("número de bloque %d fuera de rango (0-%d)", blockNum, bm.BlockCount-1)
 	}
 
+	if bm.BadBlocks[blockNum] {
+		return fmt.Errorf("el bloque %d está dañado y no se puede liberar", blockNum)
+	}
+
 	return bm.ClearBit(bm.BlockBitmap, blockNum)
 }
 
+// MarkBadBlock agrega un bloque a la lista de dañados y lo marca ocupado en el bitmap.
+// Retorna true si el bloque estaba libre (y por lo tanto se descuenta de los libres).
+func (bm *BitmapManager) MarkBadBlock(blockNum int) (bool, error) {
+	if blockNum < 0 || blockNum >= bm.BlockCount {
+		return false, fmt.Errorf("número de bloque %d fuera de rango (0-%d)", blockNum, bm.BlockCount-1)
+	}
+
+	isBusy, _ := bm.IsBitSet(bm.BlockBitmap, blockNum)
+	bm.BadBlocks[blockNum] = true
+	_ = bm.SetBit(bm.BlockBitmap, blockNum)
+	return !isBusy, nil
+}
+
 // ReserveInitialBlocks reserva los bloques e inodos iniciales del sistema
 // (superbloque, bitmaps, inodos raíz, etc.)
 func (bm *BitmapManager) ReserveInitialBlocks(reservedInodes, reservedBlocks int) {
@@ -213,6 +235,9 @@ func (bm *BitmapManager) FindFirstFreeInode() int {
 // Retorna el número de bloque o -1 si no hay bloques disponibles
 func (bm *BitmapManager) FindFirstFreeBlock() int {
 	for i := 0; i < bm.BlockCount; i++ {
+		if bm.BadBlocks[i] {
+			continue
+		}
 		isBusy, _ := bm.IsBitSet(bm.BlockBitmap, i)
 		if !isBusy {
 			return i
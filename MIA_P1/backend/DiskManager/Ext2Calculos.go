@@ -16,6 +16,7 @@ const (
 type EXT2FormatInfo struct {
 	PartitionSize      int64   // Tamaño de la partición en bytes
 	SuperBlockSize     int64   // Tamaño del superbloque
+	JournalSize        int64   // Tamaño del journal (n entradas en EXT3, 0 en EXT2)
 	InodeSize          int64   // Tamaño de cada inodo
 	BlockSize          int64   // Tamaño de cada bloque
 	InodeCount         int     // Número de inodos (n)
@@ -32,13 +33,25 @@ type EXT2FormatInfo struct {
 // CalculateEXT2Format calcula la estructura según la fórmula:
 // tamaño_particion = sizeOf(superblock) + n + 3n + n*sizeOf(inodos) + 3n*sizeOf(block)
 func CalculateEXT2Format(partitionSize int64) *EXT2FormatInfo {
+	return calculateFormat(partitionSize, 0)
+}
+
+// CalculateEXT3Format calcula la estructura de EXT3, que agrega una entrada de journal
+// por inodo justo después del superbloque:
+// tamaño_particion = sizeOf(superblock) + n*sizeOf(journal) + n + 3n + n*sizeOf(inodos) + 3n*sizeOf(block)
+func CalculateEXT3Format(partitionSize int64) *EXT2FormatInfo {
+	return calculateFormat(partitionSize, JOURNAL_ENTRY_SIZE)
+}
+
+// calculateFormat despeja n con journalEntrySize bytes de journal por inodo (0 para EXT2)
+func calculateFormat(partitionSize int64, journalEntrySize int64) *EXT2FormatInfo {
 	// Despejar n de la ecuación
-	// partitionSize = SUPERBLOCK_SIZE + n + 3n + n*INODE_SIZE + 3n*BLOCK_SIZE
-	// partitionSize = SUPERBLOCK_SIZE + n(1 + 3 + INODE_SIZE + 3*BLOCK_SIZE)
-	// n = (partitionSize - SUPERBLOCK_SIZE) / (1 + 3 + INODE_SIZE + 3*BLOCK_SIZE)
+	// partitionSize = SUPERBLOCK_SIZE + n*journalEntrySize + n + 3n + n*INODE_SIZE + 3n*BLOCK_SIZE
+	// partitionSize = SUPERBLOCK_SIZE + n(journalEntrySize + 1 + 3 + INODE_SIZE + 3*BLOCK_SIZE)
+	// n = (partitionSize - SUPERBLOCK_SIZE) / (journalEntrySize + 1 + 3 + INODE_SIZE + 3*BLOCK_SIZE)
 
 	// Según la especificación: 1 byte por inodo y 1 byte por bloque en los bitmaps
-	divisor := float64(1 + 3 + INODE_SIZE + 3*BLOCK_SIZE)
+	divisor := float64(journalEntrySize + 1 + 3 + INODE_SIZE + 3*BLOCK_SIZE)
 	n := float64(partitionSize-SUPERBLOCK_SIZE) / divisor
 
 	// Aplicar floor para obtener n, según especificación
@@ -50,24 +63,26 @@ func CalculateEXT2Format(partitionSize int64) *EXT2FormatInfo {
 	// Calcular tamaños según especificación:
 	// - Bitmap de inodos: 1 byte por inodo (no 1 bit)
 	// - Bitmap de bloques: 1 byte por bloque (no 1 bit)
+	journalSize := int64(inodeCount) * journalEntrySize
 	inodeBitmapSize := int64(inodeCount)
 	blockBitmapSize := int64(blockCount)
 	inodeTableSize := int64(inodeCount) * INODE_SIZE
 	dataBlocksSize := int64(blockCount) * BLOCK_SIZE
 
 	// Calcular espacio total usado
-	totalUsed := int64(SUPERBLOCK_SIZE) + inodeBitmapSize + blockBitmapSize +
+	totalUsed := int64(SUPERBLOCK_SIZE) + journalSize + inodeBitmapSize + blockBitmapSize +
 		inodeTableSize + dataBlocksSize
 	freeSpace := partitionSize - totalUsed
 	usedPercentage := (float64(totalUsed) / float64(partitionSize)) * 100.0
 
 	// Calcular dirección del primer bloque de datos
-	firstDataBlockAddr := int64(SUPERBLOCK_SIZE) + inodeBitmapSize +
+	firstDataBlockAddr := int64(SUPERBLOCK_SIZE) + journalSize + inodeBitmapSize +
 		blockBitmapSize + inodeTableSize
 
 	return &EXT2FormatInfo{
 		PartitionSize:      partitionSize,
 		SuperBlockSize:     int64(SUPERBLOCK_SIZE),
+		JournalSize:        journalSize,
 		InodeSize:          INODE_SIZE,
 		BlockSize:          BLOCK_SIZE,
 		InodeCount:         inodeCount,
@@ -110,7 +125,7 @@ func ValidateEXT2Format(info *EXT2FormatInfo) bool {
 
 // GetInodesAndBlocksStart calcula las direcciones de inicio de cada sección
 func GetInodesAndBlocksStart(info *EXT2FormatInfo) (inodeBitmapStart, blockBitmapStart, inodeTableStart, dataBlocksStart int64) {
-	inodeBitmapStart = info.SuperBlockSize
+	inodeBitmapStart = info.SuperBlockSize + info.JournalSize
 	blockBitmapStart = inodeBitmapStart + info.InodeBitmapSize
 	inodeTableStart = blockBitmapStart + info.BlockBitmapSize
 	dataBlocksStart = inodeTableStart + info.InodeTableSize
@@ -9,29 +9,34 @@ import (
 	"time"
 )
 
-// CreateEXT2Directory crea un directorio en el sistema de archivos EXT2
+// CreateEXT2Directory crea un directorio en el sistema de archivos EXT2. En EXT3 la
+// operación se registra en el journal antes de aplicarla.
 func CreateEXT2Directory(id, path string, owner, ownerGroup string, perms []byte) error {
+	op := journalOp{operation: JOURNAL_MKDIR, path: path, owner: owner, group: ownerGroup, perms: perms}
+	return journalOperation(id, op, func() error {
+		return createEXT2Directory(id, path, owner, ownerGroup, perms)
+	})
+}
+
+func createEXT2Directory(id, path string, owner, ownerGroup string, perms []byte) error {
 	fmt.Printf("CreateEXT2Directory: Creando directorio '%s'\n", path)
 
-	// 1. Verificar la partición montada
+	// 1. Verificar la partición montada y que admita escrituras
 	mountedPartition, err := FindMountedPartitionById(id)
 	if err != nil {
 		return fmt.Errorf("partición no encontrada: %v", err)
 	}
+	if err := CheckWritable(id); err != nil {
+		return err
+	}
 
 	// 2. Abrir el disco en modo exclusivo para evitar interferencias
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return fmt.Errorf("error al abrir disco: %v", err)
 	}
 	defer file.Close()
 
-	// 3. Obtener la posición de inicio de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return fmt.Errorf("error obteniendo detalles de partición: %v", err)
-	}
-
 	// 4. Leer el superbloque
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -339,6 +344,10 @@ func CreateEXT2Directory(id, path string, owner, ownerGroup string, perms []byte
 func CreateEXT2DirectoryRecursive(id, path string, owner, ownerGroup string, perms []byte) error {
 	fmt.Printf("CreateEXT2DirectoryRecursive: Creando ruta '%s'\n", path)
 
+	if err := CheckWritable(id); err != nil {
+		return err
+	}
+
 	// Normalizar la ruta
 	if path == "" || path == "/" {
 		return nil // La raíz ya existe, nada que hacer
@@ -410,18 +419,12 @@ func fileExistsAndType(id, path string) (exists bool, isDir bool, err error) {
 	}
 
 	// 2. Abrir el disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, false, err
 	}
 	defer file.Close()
 
-	// 3. Obtener la posición de inicio de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, false, err
-	}
-
 	// 4. Leer el superbloque
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -444,7 +447,7 @@ func fileExistsAndType(id, path string) (exists bool, isDir bool, err error) {
 }
 
 // writePointerBlockToDisc escribe un bloque de punteros al disco
-func writePointerBlockToDisc(file *os.File, pointerBlock *PointerBlock) error {
+func writePointerBlockToDisc(file PartitionFile, pointerBlock *PointerBlock) error {
 	for i := 0; i < POINTERS_PER_BLOCK; i++ {
 		err := binary.Write(file, binary.LittleEndian, &pointerBlock.BPointers[i])
 		if err != nil {
@@ -455,7 +458,7 @@ func writePointerBlockToDisc(file *os.File, pointerBlock *PointerBlock) error {
 }
 
 // readPointerBlockFromDisc lee un bloque de punteros del disco
-func readPointerBlockFromDisc(file *os.File, blockSize int64) (*PointerBlock, error) {
+func readPointerBlockFromDisc(file PartitionFile, blockSize int64) (*PointerBlock, error) {
 	pointerBlock := &PointerBlock{}
 
 	// Leer POINTERS_PER_BLOCK punteros (int32)
@@ -471,7 +474,7 @@ func readPointerBlockFromDisc(file *os.File, blockSize int64) (*PointerBlock, er
 
 // findSafeBlocksForDirectory encuentra y reserva bloques para un directorio,
 // incluyendo la posibilidad de usar bloques indirectos si es necesario
-func findSafeBlocksForDirectory(file *os.File, startByte int64, superblock *SuperBlock,
+func findSafeBlocksForDirectory(file PartitionFile, startByte int64, superblock *SuperBlock,
 	blockBitmap []byte, initialEntriesCount int) ([]int32, int32, error) {
 
 	// Número de entradas que caben en un bloque
@@ -614,7 +617,7 @@ func initializeDirectoryBlock(dirBlock *DirectoryBlock, selfInodeNum, parentInod
 }
 
 // initializeDirectoryBlocks inicializa todos los bloques de un directorio
-func initializeDirectoryBlocks(file *os.File, startByte int64, superblock *SuperBlock,
+func initializeDirectoryBlocks(file PartitionFile, startByte int64, superblock *SuperBlock,
 	blocks []int32, selfInodeNum, parentInodeNum int32, isRootDir bool) error {
 
 	for i, blockNum := range blocks {
@@ -652,7 +655,7 @@ func initializeDirectoryBlocks(file *os.File, startByte int64, superblock *Super
 }
 
 // findEmptySpaceInDirectoryBlocks busca un espacio vacío en los bloques de un directorio
-func findEmptySpaceInDirectoryBlocks(file *os.File, startByte int64, superblock *SuperBlock,
+func findEmptySpaceInDirectoryBlocks(file PartitionFile, startByte int64, superblock *SuperBlock,
 	inode *Inode) (blockNum int32, entryIdx int, err error) {
 	blocksStart := startByte + int64(superblock.SBlockStart)
 
@@ -730,7 +733,7 @@ func findEmptySpaceInDirectoryBlocks(file *os.File, startByte int64, superblock
 }
 
 // addBlockToDirectory añade un nuevo bloque a un directorio, manejando indirectos si es necesario
-func addBlockToDirectory(file *os.File, startByte int64, superblock *SuperBlock,
+func addBlockToDirectory(file PartitionFile, startByte int64, superblock *SuperBlock,
 	inode *Inode, blockBitmap []byte) (int32, error) {
 
 	// 1. Encontrar un bloque libre
@@ -850,7 +853,7 @@ func addBlockToDirectory(file *os.File, startByte int64, superblock *SuperBlock,
 }
 
 // loadInodeBitmap carga el bitmap de inodos
-func loadInodeBitmap(file *os.File, startByte int64, superblock *SuperBlock) ([]byte, error) {
+func loadInodeBitmap(file PartitionFile, startByte int64, superblock *SuperBlock) ([]byte, error) {
 	// Tamaño del bitmap en bytes (redondeado hacia arriba)
 	bitmapSize := (superblock.SInodesCount + 7) / 8
 
@@ -871,7 +874,7 @@ func loadInodeBitmap(file *os.File, startByte int64, superblock *SuperBlock) ([]
 }
 
 // loadBlockBitmap carga el bitmap de bloques
-func loadBlockBitmap(file *os.File, startByte int64, superblock *SuperBlock) ([]byte, error) {
+func loadBlockBitmap(file PartitionFile, startByte int64, superblock *SuperBlock) ([]byte, error) {
 	// Tamaño del bitmap en bytes (redondeado hacia arriba)
 	bitmapSize := (superblock.SBlocksCount + 7) / 8
 
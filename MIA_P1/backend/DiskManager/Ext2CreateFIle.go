@@ -10,29 +10,34 @@ import (
 )
 
 // CreateEXT2File crea un archivo con contenido en el sistema de archivos EXT2
-// Implementación segura para evitar corrupción de otros archivos
+// Implementación segura para evitar corrupción de otros archivos. En EXT3 la operación
+// se registra en el journal antes de aplicarla.
 func CreateEXT2File(id, path, content string, owner, ownerGroup string, perms []byte) error {
+	op := journalOp{operation: JOURNAL_MKFILE, path: path, content: content, owner: owner, group: ownerGroup, perms: perms}
+	return journalOperation(id, op, func() error {
+		return createEXT2File(id, path, content, owner, ownerGroup, perms)
+	})
+}
+
+func createEXT2File(id, path, content string, owner, ownerGroup string, perms []byte) error {
 	fmt.Printf("CreateEXT2File: Creando archivo '%s'\n", path)
 
-	// 1. Verificar la partición montada
+	// 1. Verificar la partición montada y que admita escrituras
 	mountedPartition, err := FindMountedPartitionById(id)
 	if err != nil {
 		return fmt.Errorf("partición no encontrada: %v", err)
 	}
+	if err := CheckWritable(id); err != nil {
+		return err
+	}
 
 	// 2. Abrir el disco en modo exclusivo para evitar interferencias
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return fmt.Errorf("error al abrir disco: %v", err)
 	}
 	defer file.Close()
 
-	// 3. Obtener la posición de inicio de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return fmt.Errorf("error obteniendo detalles de partición: %v", err)
-	}
-
 	// 4. Leer el superbloque
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -552,7 +557,7 @@ func CreateEXT2File(id, path, content string, owner, ownerGroup string, perms []
 // Funciones auxiliares seguras
 
 // identifyCriticalBlocks identifica bloques usados por archivos críticos del sistema
-func identifyCriticalBlocks(file *os.File, startByte int64, sb *SuperBlock) map[int32]bool {
+func identifyCriticalBlocks(file PartitionFile, startByte int64, sb *SuperBlock) map[int32]bool {
 	criticalBlocks := make(map[int32]bool)
 
 	// Proteger especialmente los bloques de users.txt (inodo 3)
@@ -584,6 +589,16 @@ func identifyCriticalBlocks(file *os.File, startByte int64, sb *SuperBlock) map[
 		criticalBlocks[i] = true
 	}
 
+	// Los bloques dañados y su lista nunca se asignan, aunque su bit del bitmap se haya limpiado
+	if badBlocks, listBlock, err := readBadBlocks(file, startByte, sb); err == nil {
+		for _, blockNum := range badBlocks {
+			criticalBlocks[blockNum] = true
+		}
+		if listBlock >= 0 {
+			criticalBlocks[listBlock] = true
+		}
+	}
+
 	return criticalBlocks
 }
 
@@ -631,7 +646,7 @@ func findSafeBlockNum(bitmap []byte, maxBlocks int, criticalBlocks map[int32]boo
 }
 
 // findFirstDirectoryBlock encuentra el primer bloque de un directorio
-func findFirstDirectoryBlock(file *os.File, startByte int64, sb *SuperBlock, inode *Inode) (*DirectoryBlock, int32, error) {
+func findFirstDirectoryBlock(file PartitionFile, startByte int64, sb *SuperBlock, inode *Inode) (*DirectoryBlock, int32, error) {
 	// Solo consideramos el primer bloque directo por ahora
 	if inode.IBlock[0] <= 0 {
 		return nil, -1, fmt.Errorf("directorio no tiene bloques asignados")
@@ -675,7 +690,7 @@ func compareInodes(a, b *Inode) bool {
 }
 
 // findInodeByPath implementación optimizada para encontrar un inodo por su ruta
-func FindInodeByPath(file *os.File, startByte int64, superblock *SuperBlock, path string) (int, *Inode, error) {
+func FindInodeByPath(file PartitionFile, startByte int64, superblock *SuperBlock, path string) (int, *Inode, error) {
 	fmt.Printf("Buscando inodo para ruta: %s\n", path)
 
 	if path == "" || path == "/" {
@@ -810,7 +825,7 @@ func FindInodeByPath(file *os.File, startByte int64, superblock *SuperBlock, pat
 }
 
 // Función para manejar bloques indirectos triples
-func allocateTripleIndirectBlock(file *os.File, blockBitmap []byte, blockSize int, startByte int64,
+func allocateTripleIndirectBlock(file PartitionFile, blockBitmap []byte, blockSize int, startByte int64,
 	superblock *SuperBlock, fileBlocks *[]int32, tripleIndirectBlockNum *int32,
 	blocksNeeded int, criticalBlocks map[int32]bool) error {
 
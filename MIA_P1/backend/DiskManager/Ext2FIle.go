@@ -13,8 +13,32 @@ const (
 	FILE_APPEND        // Añadir contenido al final
 )
 
-// EXT2FileOperation realiza operaciones de lectura/escritura/anexo en archivos EXT2
+// MAX_FILE_BLOCKS es la cantidad de bloques de datos que alcanza un inodo con sus
+// apuntadores directos y los indirectos simple, doble y triple
+const MAX_FILE_BLOCKS = INDIRECT_BLOCK_INDEX + POINTERS_PER_BLOCK +
+	POINTERS_PER_BLOCK*POINTERS_PER_BLOCK + POINTERS_PER_BLOCK*POINTERS_PER_BLOCK*POINTERS_PER_BLOCK
+
+// EXT2FileOperation realiza operaciones de lectura/escritura/anexo en archivos EXT2.
+// En EXT3 las escrituras y anexos se registran en el journal antes de aplicarlos.
 func EXT2FileOperation(id string, path string, operation int, content string) (string, error) {
+	if operation == FILE_READ {
+		return ext2FileOperation(id, path, operation, content)
+	}
+
+	op := journalOp{operation: JOURNAL_WRITE, path: path, content: content}
+	if operation == FILE_APPEND {
+		op.operation = JOURNAL_APPEND
+	}
+	var result string
+	err := journalOperation(id, op, func() error {
+		var err error
+		result, err = ext2FileOperation(id, path, operation, content)
+		return err
+	})
+	return result, err
+}
+
+func ext2FileOperation(id string, path string, operation int, content string) (string, error) {
 	// 1. Validar que la ruta existe y es un archivo
 	exists, pathType, err := ValidateEXT2Path(id, path)
 	if err != nil {
@@ -29,25 +53,26 @@ func EXT2FileOperation(id string, path string, operation int, content string) (s
 		return "", fmt.Errorf("La ruta %s no es un archivo", path)
 	}
 
-	// 2. Obtener la partición montada
+	// 2. Obtener la partición montada; escribir o anexar no se permite en solo lectura
 	mountedPartition, err := FindMountedPartitionById(id)
 	if err != nil {
 		return "", fmt.Errorf("Error: %s", err)
 	}
+	if operation != FILE_READ && mountedPartition.ReadOnly {
+		return "", fmt.Errorf("la partición %s está montada en modo solo lectura", id)
+	}
 
-	// 3. Abrir el disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	// 3. Abrir el disco (solo lectura si la operación no escribe)
+	openFlags := os.O_RDWR
+	if operation == FILE_READ {
+		openFlags = os.O_RDONLY
+	}
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, openFlags)
 	if err != nil {
 		return "", fmt.Errorf("Error al abrir el disco: %s", err)
 	}
 	defer file.Close()
 
-	// 4. Obtener detalles de la partición y superbloque
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return "", fmt.Errorf("Error al obtener detalles de la partición: %s", err)
-	}
-
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
 		return "", fmt.Errorf("Error al posicionarse para leer superbloque: %s", err)
@@ -83,22 +108,26 @@ func EXT2FileOperation(id string, path string, operation int, content string) (s
 	}
 }
 
-// readFileContent lee todo el contenido de un archivo
-func readFileContent(file *os.File, startByte int64, sb *SuperBlock, inode *Inode) (string, error) {
+// readFileContent lee todo el contenido de un archivo, siguiendo los bloques directos y los
+// indirectos simple, doble y triple. No actualiza IAtime ni escribe nada en el disco, así
+// que se puede usar en particiones montadas como solo lectura.
+func readFileContent(file PartitionFile, startByte int64, sb *SuperBlock, inode *Inode) (string, error) {
 	// Determinar cuánto contenido necesitamos leer
-	contentSize := inode.ISize
+	contentSize := int(inode.ISize)
 	if contentSize <= 0 {
 		return "", nil // Archivo vacío
 	}
 
+	dataBlocks, _, err := inodeBlocks(file, startByte, sb, inode)
+	if err != nil {
+		return "", err
+	}
+
 	// Buffer para almacenar el contenido
 	contentBuffer := make([]byte, contentSize)
-	bytesRead := int32(0)
-
-	// Leer los bloques directos primero
-	for i := 0; i < 12 && bytesRead < contentSize; i++ {
-		blockNum := inode.IBlock[i]
-		if blockNum <= 0 {
+	bytesRead := 0
+	for _, blockNum := range dataBlocks {
+		if bytesRead >= contentSize {
 			break
 		}
 
@@ -110,96 +139,26 @@ func readFileContent(file *os.File, startByte int64, sb *SuperBlock, inode *Inod
 		}
 
 		// Determinar cuántos bytes leer de este bloque
-		bytesToRead := min(int(sb.SBlockSize), int(contentSize-bytesRead))
-
-		// Leer el bloque - CORREGIDO: Convertir a int para los índices de slice
-		bufferPosInt := int(bytesRead)
-		n, err := file.Read(contentBuffer[bufferPosInt : bufferPosInt+bytesToRead])
+		bytesToRead := min(int(sb.SBlockSize), contentSize-bytesRead)
+		n, err := file.Read(contentBuffer[bytesRead : bytesRead+bytesToRead])
 		if err != nil {
 			return "", fmt.Errorf("Error al leer bloque: %s", err)
 		}
 
-		bytesRead += int32(n)
-	}
-
-	// Leer el indirecto simple si es necesario
-	if bytesRead < contentSize && inode.IBlock[12] > 0 {
-
+		bytesRead += n
 	}
 
 	// Convertir a string
 	return string(contentBuffer[:bytesRead]), nil
 }
 
-// writeFileContent escribe o añade contenido a un archivo
-func writeFileContent(file *os.File, startByte int64, sb *SuperBlock, inodeNum int, inode *Inode,
+// writeFileContent escribe o añade contenido a un archivo. Reutiliza los bloques que el
+// archivo ya tiene, asigna los que falten (pasando a los indirectos si hace falta) y libera
+// los que sobren cuando el contenido se achica.
+func writeFileContent(file PartitionFile, startByte int64, sb *SuperBlock, inodeNum int, inode *Inode,
 	newContent string, isAppend bool) (string, error) {
 
-	// Calcular el tamaño total después de la operación
-	var totalSize int32
-	if isAppend {
-		totalSize = inode.ISize + int32(len(newContent))
-	} else {
-		totalSize = int32(len(newContent))
-	}
-
-	// Verificar si necesitamos más bloques
-	neededBlocks := (totalSize + sb.SBlockSize - 1) / sb.SBlockSize
-	currentBlocks := 0
-
-	// Contar bloques actuales
-	for i := 0; i < 12 && inode.IBlock[i] > 0; i++ {
-		currentBlocks++
-	}
-
-	// Si necesitamos más bloques, asignarlos
-	newBlocks := []int32{}
-	if neededBlocks > int32(currentBlocks) {
-		// Leer el bitmap de bloques
-		bmBlockPos := startByte + int64(sb.SBmBlockStart)
-		_, err := file.Seek(bmBlockPos, 0)
-		if err != nil {
-			return "", fmt.Errorf("Error al posicionarse en bitmap de bloques: %s", err)
-		}
-
-		bmBlocks := make([]byte, sb.SBlocksCount/8+1)
-		_, err = file.Read(bmBlocks)
-		if err != nil {
-			return "", fmt.Errorf("Error al leer bitmap de bloques: %s", err)
-		}
-
-		// Encontrar bloques libres
-		blocksNeeded := int(neededBlocks) - currentBlocks
-		for i := 0; i < int(sb.SBlocksCount) && len(newBlocks) < blocksNeeded; i++ {
-			bytePos := i / 8
-			bitPos := i % 8
-
-			if bytePos < len(bmBlocks) && (bmBlocks[bytePos]&(1<<bitPos)) == 0 {
-				// Marcar bloque como usado
-				bmBlocks[bytePos] |= (1 << bitPos)
-				newBlocks = append(newBlocks, int32(i))
-			}
-		}
-
-		if len(newBlocks) < blocksNeeded {
-			return "", fmt.Errorf("No hay suficientes bloques libres para esta operación")
-		}
-
-		// Escribir bitmap actualizado
-		_, err = file.Seek(bmBlockPos, 0)
-		if err == nil {
-			file.Write(bmBlocks)
-		}
-
-		// Actualizar el superbloque
-		sb.SFreeBlocksCount -= int32(len(newBlocks))
-		_, err = file.Seek(startByte, 0)
-		if err == nil {
-			writeSuperBlockToDisc(file, sb)
-		}
-	}
-
-	// Preparar buffer para escribir
+	// Preparar el contenido completo del archivo
 	var contentToWrite []byte
 	if isAppend {
 		// Leer el contenido actual primero
@@ -213,53 +172,73 @@ func writeFileContent(file *os.File, startByte int64, sb *SuperBlock, inodeNum i
 		contentToWrite = []byte(newContent)
 	}
 
-	// Asignar los nuevos bloques al inodo si es necesario
-	newBlockIndex := 0
-	for i := currentBlocks; i < int(neededBlocks); i++ {
-		if i < 12 { // Solo usamos bloques directos por ahora
-			inode.IBlock[i] = newBlocks[newBlockIndex]
-			newBlockIndex++
-		}
+	// Calcular los bloques necesarios (mínimo uno, igual que al crear el archivo)
+	blockSize := int(sb.SBlockSize)
+	neededBlocks := max((len(contentToWrite)+blockSize-1)/blockSize, 1)
+	if neededBlocks > MAX_FILE_BLOCKS {
+		return "", fmt.Errorf("El contenido necesita %d bloques y un archivo admite como máximo %d", neededBlocks, MAX_FILE_BLOCKS)
 	}
 
-	// Escribir el contenido a los bloques
-	contentPos := 0
-	for i := 0; i < int(neededBlocks); i++ {
-		if i >= 12 {
-			break // No manejamos bloques indirectos por ahora
-		}
-
-		blockNum := inode.IBlock[i]
-		if blockNum <= 0 {
-			break
-		}
+	dataBlocks, pointerBlocks, err := inodeBlocks(file, startByte, sb, inode)
+	if err != nil {
+		return "", err
+	}
+	blockBitmap, err := loadBlockBitmap(file, startByte, sb)
+	if err != nil {
+		return "", err
+	}
+	criticalBlocks := identifyCriticalBlocks(file, startByte, sb)
 
-		// Posicionarse al inicio del bloque
-		blockPos := startByte + int64(sb.SBlockStart) + int64(blockNum)*int64(sb.SBlockSize)
-		_, err := file.Seek(blockPos, 0)
-		if err != nil {
-			return "", fmt.Errorf("Error al posicionarse para escribir bloque: %s", err)
+	// Asignar los bloques que falten o liberar los que sobren
+	for len(dataBlocks) < neededBlocks {
+		blockNum := findSafeBlockNum(blockBitmap, int(sb.SBlocksCount), criticalBlocks)
+		if blockNum < 0 {
+			return "", fmt.Errorf("No hay suficientes bloques libres para esta operación")
 		}
+		blockBitmap[blockNum/8] |= 1 << (blockNum % 8)
+		sb.AllocateBlock()
+		dataBlocks = append(dataBlocks, int32(blockNum))
+	}
+	for _, blockNum := range dataBlocks[neededBlocks:] {
+		blockBitmap[blockNum/8] &= ^(1 << (blockNum % 8))
+		sb.FreeBlock()
+	}
+	dataBlocks = dataBlocks[:neededBlocks]
 
-		// Determinar cuántos bytes escribir en este bloque
-		bytesToWrite := min(int(sb.SBlockSize), len(contentToWrite)-contentPos)
-		if bytesToWrite <= 0 {
-			break
-		}
+	// Enlazar los bloques en el inodo, reutilizando sus bloques de apuntadores
+	if err := setInodeBlocks(file, startByte, sb, blockBitmap, criticalBlocks, inode, dataBlocks, pointerBlocks); err != nil {
+		return "", err
+	}
 
+	// Escribir el contenido a los bloques
+	for i, blockNum := range dataBlocks {
 		// Crear buffer del tamaño exacto del bloque (inicializado a ceros)
-		blockBuffer := make([]byte, sb.SBlockSize)
-
-		// Copiar el contenido al inicio del buffer
-		copy(blockBuffer, contentToWrite[contentPos:contentPos+bytesToWrite])
+		blockBuffer := make([]byte, blockSize)
+		if i*blockSize < len(contentToWrite) {
+			copy(blockBuffer, contentToWrite[i*blockSize:])
+		}
 
-		// Escribir el buffer completo
-		_, err = file.Write(blockBuffer)
-		if err != nil {
+		blockPos := startByte + int64(sb.SBlockStart) + int64(blockNum)*int64(blockSize)
+		if _, err := file.Seek(blockPos, 0); err != nil {
+			return "", fmt.Errorf("Error al posicionarse para escribir bloque: %s", err)
+		}
+		if _, err := file.Write(blockBuffer); err != nil {
 			return "", fmt.Errorf("Error al escribir bloque: %s", err)
 		}
+	}
 
-		contentPos += bytesToWrite
+	// Escribir bitmap y superbloque actualizados
+	if _, err := file.Seek(startByte+int64(sb.SBmBlockStart), 0); err != nil {
+		return "", fmt.Errorf("Error al posicionarse en bitmap de bloques: %s", err)
+	}
+	if _, err := file.Write(blockBitmap); err != nil {
+		return "", fmt.Errorf("Error al escribir bitmap de bloques: %s", err)
+	}
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return "", fmt.Errorf("Error al posicionarse para actualizar superbloque: %s", err)
+	}
+	if err := writeSuperBlockToDisc(file, sb); err != nil {
+		return "", fmt.Errorf("Error al actualizar superbloque: %s", err)
 	}
 
 	// Actualizar tamaño y timestamp en el inodo
@@ -268,7 +247,7 @@ func writeFileContent(file *os.File, startByte int64, sb *SuperBlock, inodeNum i
 
 	// Escribir el inodo actualizado
 	inodePos := startByte + int64(sb.SInodeStart) + int64(inodeNum)*int64(sb.SInodeSize)
-	_, err := file.Seek(inodePos, 0)
+	_, err = file.Seek(inodePos, 0)
 	if err != nil {
 		return "", fmt.Errorf("Error al posicionarse para actualizar inodo: %s", err)
 	}
@@ -285,3 +264,77 @@ func writeFileContent(file *os.File, startByte int64, sb *SuperBlock, inodeNum i
 		return fmt.Sprintf("Archivo sobrescrito exitosamente. Tamaño: %d bytes", len(contentToWrite)), nil
 	}
 }
+
+// setInodeBlocks enlaza dataBlocks en el inodo: los primeros en los apuntadores directos y
+// el resto en los indirectos simple, doble y triple. Los bloques de apuntadores se toman de
+// oldPointerBlocks mientras alcancen; los que falten se asignan y los que sobren se liberan.
+func setInodeBlocks(file PartitionFile, startByte int64, sb *SuperBlock, blockBitmap []byte,
+	criticalBlocks map[int32]bool, inode *Inode, dataBlocks []int32, oldPointerBlocks []int32) error {
+
+	nextPointerBlock := func() (int32, error) {
+		if len(oldPointerBlocks) > 0 {
+			blockNum := oldPointerBlocks[0]
+			oldPointerBlocks = oldPointerBlocks[1:]
+			return blockNum, nil
+		}
+		blockNum := findSafeBlockNum(blockBitmap, int(sb.SBlocksCount), criticalBlocks)
+		if blockNum < 0 {
+			return -1, fmt.Errorf("No hay bloques libres para los apuntadores indirectos")
+		}
+		blockBitmap[blockNum/8] |= 1 << (blockNum % 8)
+		sb.AllocateBlock()
+		return int32(blockNum), nil
+	}
+
+	// build arma un bloque de apuntadores; level 1 apunta a bloques de datos
+	var build func(level int) (int32, error)
+	build = func(level int) (int32, error) {
+		blockNum, err := nextPointerBlock()
+		if err != nil {
+			return -1, err
+		}
+		pointerBlock := NewPointerBlock()
+		for i := 0; i < POINTERS_PER_BLOCK && len(dataBlocks) > 0; i++ {
+			if level == 1 {
+				pointerBlock.BPointers[i] = dataBlocks[0]
+				dataBlocks = dataBlocks[1:]
+			} else if pointerBlock.BPointers[i], err = build(level - 1); err != nil {
+				return -1, err
+			}
+		}
+
+		blockPos := startByte + int64(sb.SBlockStart) + int64(blockNum)*int64(sb.SBlockSize)
+		if _, err := file.Seek(blockPos, 0); err != nil {
+			return -1, fmt.Errorf("Error al posicionarse para escribir bloque de apuntadores: %s", err)
+		}
+		if err := writePointerBlockToDisc(file, pointerBlock); err != nil {
+			return -1, err
+		}
+		return blockNum, nil
+	}
+
+	for i := 0; i < INDIRECT_BLOCK_INDEX; i++ {
+		inode.IBlock[i] = -1
+		if len(dataBlocks) > 0 {
+			inode.IBlock[i] = dataBlocks[0]
+			dataBlocks = dataBlocks[1:]
+		}
+	}
+	for level, index := range []int{INDIRECT_BLOCK_INDEX, DOUBLE_INDIRECT_BLOCK_INDEX, TRIPLE_INDIRECT_BLOCK_INDEX} {
+		inode.IBlock[index] = -1
+		if len(dataBlocks) > 0 {
+			blockNum, err := build(level + 1)
+			if err != nil {
+				return err
+			}
+			inode.IBlock[index] = blockNum
+		}
+	}
+
+	// Los bloques de apuntadores que ya no se usan quedan libres
+	for _, blockNum := range oldPointerBlocks {
+		blockBitmap[blockNum/8] &= ^(1 << (blockNum % 8))
+		sb.FreeBlock()
+	}
+	return nil
+}
@@ -7,8 +7,16 @@ import (
 )
 
 // OverwriteEXT2File sobrescribe el contenido de un archivo existente en el sistema de archivos EXT2
-// Implementación segura para evitar corrupción de otros archivos
+// Implementación segura para evitar corrupción de otros archivos. En EXT3 la operación se
+// registra en el journal antes de aplicarla.
 func OverwriteEXT2File(id, path, newContent string) error {
+	op := journalOp{operation: JOURNAL_OVERWRITE, path: path, content: newContent}
+	return journalOperation(id, op, func() error {
+		return overwriteEXT2File(id, path, newContent)
+	})
+}
+
+func overwriteEXT2File(id, path, newContent string) error {
 	fmt.Printf("OverwriteEXT2File: Sobrescribiendo archivo '%s'\n", path)
 
 	// PROTECCIÓN CRÍTICA INICIAL
@@ -16,26 +24,23 @@ func OverwriteEXT2File(id, path, newContent string) error {
 		return fmt.Errorf("no se permite sobrescribir directamente users.txt por seguridad")
 	}
 
-	// 1. Verificar la partición montada
+	// 1. Verificar la partición montada y que admita escrituras
 	mountedPartition, err := FindMountedPartitionById(id)
 	if err != nil {
 		return fmt.Errorf("partición no encontrada: %v", err)
 	}
+	if err := CheckWritable(id); err != nil {
+		return err
+	}
 
 	// 2. Abrir el disco en modo exclusivo para evitar interferencias
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return fmt.Errorf("error al abrir disco: %v", err)
 	}
 	// Asegurarnos de que el archivo se cierre al finalizar, pase lo que pase
 	defer file.Close()
 
-	// 3. Obtener la posición de inicio de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return fmt.Errorf("error obteniendo detalles de partición: %v", err)
-	}
-
 	// 4. Leer el superbloque
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -0,0 +1,359 @@
+package DiskManager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RemoveEXT2Path elimina un archivo, o una carpeta con todo su contenido, de una partición
+// EXT2. Cada archivo y carpeta se elimina solo si el usuario activo tiene permiso de
+// escritura sobre él: los que no lo tienen quedan intactos con todo su contenido, igual que
+// las carpetas que los contienen. Retorna las rutas que no se eliminaron por falta de permisos.
+// En EXT3 la operación se registra en el journal con el usuario y grupo que la hicieron.
+func RemoveEXT2Path(id, path, user, group string) ([]string, error) {
+	var denied []string
+	op := journalOp{operation: JOURNAL_REMOVE, path: path, owner: user, group: group}
+	err := journalOperation(id, op, func() error {
+		var err error
+		denied, err = removeEXT2Path(id, path)
+		return err
+	})
+	return denied, err
+}
+
+// ext2Remover guarda el estado de una eliminación recursiva: los bitmaps que se van
+// liberando y las rutas que se conservaron por falta de permisos
+type ext2Remover struct {
+	file        PartitionFile
+	startByte   int64
+	sb          *SuperBlock
+	inodeBitmap []byte
+	blockBitmap []byte
+	denied      []string
+	freedInodes int
+	freedBlocks int
+}
+
+func removeEXT2Path(id, path string) ([]string, error) {
+	cleanPath := filepath.Clean("/" + strings.TrimPrefix(path, "/"))
+	if cleanPath == "/" {
+		return nil, fmt.Errorf("no se puede eliminar la carpeta raíz")
+	}
+
+	// 1. Verificar la partición montada y que admita escrituras
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil {
+		return nil, fmt.Errorf("partición no encontrada: %v", err)
+	}
+	if err := CheckWritable(id); err != nil {
+		return nil, err
+	}
+
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir disco: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return nil, fmt.Errorf("error al posicionarse para leer superbloque: %v", err)
+	}
+	superblock, err := ReadSuperBlockFromDisc(file)
+	if err != nil || superblock.SMagic != EXT2_MAGIC {
+		return nil, fmt.Errorf("la partición %s no tiene un sistema de archivos EXT2", id)
+	}
+
+	// 2. Buscar lo que se elimina y su carpeta padre
+	parentPath := filepath.Dir(cleanPath)
+	name := filepath.Base(cleanPath)
+	parentInodeNum, parentInode, err := FindInodeByPath(file, startByte, superblock, parentPath)
+	if err != nil {
+		return nil, fmt.Errorf("no existe la carpeta '%s': %v", parentPath, err)
+	}
+	inodeNum, inode, err := FindInodeByPath(file, startByte, superblock, cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("no existe '%s'", cleanPath)
+	}
+	if inodeNum == 3 {
+		return nil, fmt.Errorf("no se permite eliminar users.txt")
+	}
+	if err := CheckFilePermissions(inode, PERM_WRITE); err != nil {
+		return nil, fmt.Errorf("error de permisos en '%s': %v", cleanPath, err)
+	}
+
+	// 3. Eliminar recursivamente, liberando inodos y bloques en los bitmaps
+	inodeBitmap, err := loadInodeBitmap(file, startByte, superblock)
+	if err != nil {
+		return nil, err
+	}
+	blockBitmap, err := loadBlockBitmap(file, startByte, superblock)
+	if err != nil {
+		return nil, err
+	}
+	remover := &ext2Remover{
+		file:        file,
+		startByte:   startByte,
+		sb:          superblock,
+		inodeBitmap: inodeBitmap,
+		blockBitmap: blockBitmap,
+	}
+	removed, err := remover.removeInode(int32(inodeNum), inode, cleanPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Quitar la entrada de la carpeta padre si se eliminó por completo
+	if removed {
+		if err := remover.unlinkEntry(int32(parentInodeNum), parentInode, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Guardar bitmaps y superbloque
+	if _, err := file.Seek(startByte+int64(superblock.SBmInodeStart), 0); err != nil {
+		return nil, fmt.Errorf("error al posicionarse para actualizar bitmap de inodos: %v", err)
+	}
+	if _, err := file.Write(inodeBitmap); err != nil {
+		return nil, fmt.Errorf("error al actualizar bitmap de inodos: %v", err)
+	}
+	if _, err := file.Seek(startByte+int64(superblock.SBmBlockStart), 0); err != nil {
+		return nil, fmt.Errorf("error al posicionarse para actualizar bitmap de bloques: %v", err)
+	}
+	if _, err := file.Write(blockBitmap); err != nil {
+		return nil, fmt.Errorf("error al actualizar bitmap de bloques: %v", err)
+	}
+	superblock.SMtime = time.Now()
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return nil, fmt.Errorf("error al posicionarse para actualizar superbloque: %v", err)
+	}
+	if err := writeSuperBlockToDisc(file, superblock); err != nil {
+		return nil, fmt.Errorf("error al actualizar superbloque: %v", err)
+	}
+	if err := file.Sync(); err != nil {
+		return nil, fmt.Errorf("error al sincronizar con el disco: %v", err)
+	}
+
+	fmt.Printf("Debug: Eliminación de '%s' en %s: %d inodos y %d bloques liberados, %d rutas sin permisos\n",
+		cleanPath, id, remover.freedInodes, remover.freedBlocks, len(remover.denied))
+	return remover.denied, nil
+}
+
+// removeInode elimina un inodo y, si es una carpeta, todo su contenido. Retorna false si
+// la carpeta se conservó porque algo dentro de ella no se pudo eliminar por permisos.
+func (r *ext2Remover) removeInode(inodeNum int32, inode *Inode, path string) (bool, error) {
+	dataBlocks, pointerBlocks, err := inodeBlocks(r.file, r.startByte, r.sb, inode)
+	if err != nil {
+		return false, err
+	}
+
+	if inode.IType == INODE_FOLDER {
+		kept := false
+		removedEntries := 0
+		for _, blockNum := range dataBlocks {
+			dirBlock, err := r.readDirectoryBlock(blockNum)
+			if err != nil {
+				return false, err
+			}
+
+			changed := false
+			for _, entry := range dirBlock.ListEntries() {
+				// "." y ".." y la entrada con el nombre propio apuntan a carpetas que no se eliminan aquí
+				if entry.Name == "." || entry.Name == ".." || entry.InodeNum <= 0 || entry.InodeNum == inodeNum {
+					continue
+				}
+				childPath := strings.TrimSuffix(path, "/") + "/" + entry.Name
+
+				child, err := r.readInode(entry.InodeNum)
+				if err != nil {
+					return false, err
+				}
+				if err := CheckFilePermissions(child, PERM_WRITE); err != nil {
+					fmt.Printf("Debug: '%s' se conserva: %v\n", childPath, err)
+					r.denied = append(r.denied, childPath)
+					kept = true
+					continue
+				}
+
+				removed, err := r.removeInode(entry.InodeNum, child, childPath)
+				if err != nil {
+					return false, err
+				}
+				if !removed {
+					kept = true
+					continue
+				}
+				dirBlock.RemoveEntry(entry.Name)
+				removedEntries++
+				changed = true
+			}
+
+			if changed {
+				if err := r.writeDirectoryBlock(blockNum, dirBlock); err != nil {
+					return false, err
+				}
+			}
+		}
+
+		// La carpeta se queda con lo que no se pudo eliminar
+		if kept {
+			inode.ISize -= int32(removedEntries * 16)
+			if inode.ISize < 0 {
+				inode.ISize = 0
+			}
+			inode.IMtime = time.Now()
+			return false, r.writeInode(inodeNum, inode)
+		}
+	}
+
+	for _, blockNum := range append(dataBlocks, pointerBlocks...) {
+		r.freeBlock(blockNum)
+	}
+	r.freeInode(inodeNum)
+	return true, nil
+}
+
+// unlinkEntry quita la entrada name de la carpeta padre y actualiza su inodo
+func (r *ext2Remover) unlinkEntry(parentInodeNum int32, parentInode *Inode, name string) error {
+	dataBlocks, _, err := inodeBlocks(r.file, r.startByte, r.sb, parentInode)
+	if err != nil {
+		return err
+	}
+	for _, blockNum := range dataBlocks {
+		dirBlock, err := r.readDirectoryBlock(blockNum)
+		if err != nil {
+			return err
+		}
+		if !dirBlock.RemoveEntry(name) {
+			continue
+		}
+		if err := r.writeDirectoryBlock(blockNum, dirBlock); err != nil {
+			return err
+		}
+
+		parentInode.ISize -= 16 // Cada entrada ocupa 16 bytes
+		if parentInode.ISize < 0 {
+			parentInode.ISize = 0
+		}
+		parentInode.IMtime = time.Now()
+		return r.writeInode(parentInodeNum, parentInode)
+	}
+	return fmt.Errorf("no se encontró la entrada '%s' en la carpeta padre", name)
+}
+
+// freeBlock marca un bloque como libre en el bitmap y en el superbloque
+func (r *ext2Remover) freeBlock(blockNum int32) {
+	if r.blockBitmap[blockNum/8]&(1<<(blockNum%8)) == 0 {
+		return
+	}
+	r.blockBitmap[blockNum/8] &= ^(1 << (blockNum % 8))
+	r.sb.FreeBlock()
+	r.freedBlocks++
+}
+
+// freeInode marca un inodo como libre en el bitmap y en el superbloque
+func (r *ext2Remover) freeInode(inodeNum int32) {
+	if r.inodeBitmap[inodeNum/8]&(1<<(inodeNum%8)) == 0 {
+		return
+	}
+	r.inodeBitmap[inodeNum/8] &= ^(1 << (inodeNum % 8))
+	r.sb.FreeInode()
+	r.freedInodes++
+}
+
+func (r *ext2Remover) readInode(inodeNum int32) (*Inode, error) {
+	inodePos := r.startByte + int64(r.sb.SInodeStart) + int64(inodeNum)*int64(r.sb.SInodeSize)
+	if _, err := r.file.Seek(inodePos, 0); err != nil {
+		return nil, fmt.Errorf("error al posicionarse en inodo %d: %v", inodeNum, err)
+	}
+	inode, err := readInodeFromDisc(r.file)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer inodo %d: %v", inodeNum, err)
+	}
+	return inode, nil
+}
+
+func (r *ext2Remover) writeInode(inodeNum int32, inode *Inode) error {
+	inodePos := r.startByte + int64(r.sb.SInodeStart) + int64(inodeNum)*int64(r.sb.SInodeSize)
+	if _, err := r.file.Seek(inodePos, 0); err != nil {
+		return fmt.Errorf("error al posicionarse en inodo %d: %v", inodeNum, err)
+	}
+	if err := writeInodeToDisc(r.file, inode); err != nil {
+		return fmt.Errorf("error al escribir inodo %d: %v", inodeNum, err)
+	}
+	return nil
+}
+
+func (r *ext2Remover) readDirectoryBlock(blockNum int32) (*DirectoryBlock, error) {
+	blockPos := r.startByte + int64(r.sb.SBlockStart) + int64(blockNum)*int64(r.sb.SBlockSize)
+	if _, err := r.file.Seek(blockPos, 0); err != nil {
+		return nil, fmt.Errorf("error al posicionarse en bloque %d: %v", blockNum, err)
+	}
+	dirBlock, err := ReadDirectoryBlockFromDisc(r.file, int64(r.sb.SBlockSize))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer bloque de carpeta %d: %v", blockNum, err)
+	}
+	return dirBlock, nil
+}
+
+func (r *ext2Remover) writeDirectoryBlock(blockNum int32, dirBlock *DirectoryBlock) error {
+	blockPos := r.startByte + int64(r.sb.SBlockStart) + int64(blockNum)*int64(r.sb.SBlockSize)
+	if _, err := r.file.Seek(blockPos, 0); err != nil {
+		return fmt.Errorf("error al posicionarse en bloque %d: %v", blockNum, err)
+	}
+	if err := writeDirectoryBlockToDisc(r.file, dirBlock); err != nil {
+		return fmt.Errorf("error al escribir bloque de carpeta %d: %v", blockNum, err)
+	}
+	return nil
+}
+
+// inodeBlocks retorna los bloques de datos de un inodo en orden y, aparte, los bloques de
+// apuntadores de los indirectos simple, doble y triple que los enlazan
+func inodeBlocks(file PartitionFile, startByte int64, sb *SuperBlock, inode *Inode) ([]int32, []int32, error) {
+	var dataBlocks, pointerBlocks []int32
+	valid := func(blockNum int32) bool {
+		return blockNum > 0 && blockNum < sb.SBlocksCount
+	}
+
+	for i := 0; i < INDIRECT_BLOCK_INDEX; i++ {
+		if valid(inode.IBlock[i]) {
+			dataBlocks = append(dataBlocks, inode.IBlock[i])
+		}
+	}
+
+	// collect recorre un bloque de apuntadores; level 1 apunta a bloques de datos
+	var collect func(blockNum int32, level int) error
+	collect = func(blockNum int32, level int) error {
+		pointerBlocks = append(pointerBlocks, blockNum)
+		blockPos := startByte + int64(sb.SBlockStart) + int64(blockNum)*int64(sb.SBlockSize)
+		if _, err := file.Seek(blockPos, 0); err != nil {
+			return fmt.Errorf("error al posicionarse en bloque de apuntadores %d: %v", blockNum, err)
+		}
+		pointerBlock, err := readPointerBlockFromDisc(file, int64(sb.SBlockSize))
+		if err != nil {
+			return fmt.Errorf("error al leer bloque de apuntadores %d: %v", blockNum, err)
+		}
+		for _, pointer := range pointerBlock.BPointers {
+			if !valid(pointer) {
+				continue
+			}
+			if level == 1 {
+				dataBlocks = append(dataBlocks, pointer)
+			} else if err := collect(pointer, level-1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for level, index := range []int{INDIRECT_BLOCK_INDEX, DOUBLE_INDIRECT_BLOCK_INDEX, TRIPLE_INDIRECT_BLOCK_INDEX} {
+		if valid(inode.IBlock[index]) {
+			if err := collect(inode.IBlock[index], level+1); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return dataBlocks, pointerBlocks, nil
+}
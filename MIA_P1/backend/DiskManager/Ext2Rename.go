@@ -0,0 +1,119 @@
+package DiskManager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RenameEXT2Path cambia el nombre de un archivo o carpeta. Solo se reescribe el nombre de
+// su entrada en la carpeta padre, así que el inodo y el contenido no cambian. Requiere
+// permiso de escritura sobre la carpeta padre. En EXT3 la operación se registra en el
+// journal con el usuario y grupo que la hicieron.
+func RenameEXT2Path(id, path, newName, user, group string) error {
+	op := journalOp{operation: JOURNAL_RENAME, path: path, content: newName, owner: user, group: group}
+	return journalOperation(id, op, func() error {
+		return renameEXT2Path(id, path, newName)
+	})
+}
+
+func renameEXT2Path(id, path, newName string) error {
+	cleanPath := filepath.Clean("/" + strings.TrimPrefix(path, "/"))
+	if cleanPath == "/" {
+		return fmt.Errorf("no se puede renombrar la carpeta raíz")
+	}
+	if newName == "" || newName == "." || newName == ".." || strings.Contains(newName, "/") {
+		return fmt.Errorf("el nombre '%s' no es válido", newName)
+	}
+	if len(newName) > B_NAME_SIZE {
+		return fmt.Errorf("nombre demasiado largo: %s (máx %d caracteres)", newName, B_NAME_SIZE)
+	}
+
+	// 1. Verificar la partición montada y que admita escrituras
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil {
+		return fmt.Errorf("partición no encontrada: %v", err)
+	}
+	if err := CheckWritable(id); err != nil {
+		return err
+	}
+
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
+	if err != nil {
+		return fmt.Errorf("error al abrir disco: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return fmt.Errorf("error al posicionarse para leer superbloque: %v", err)
+	}
+	superblock, err := ReadSuperBlockFromDisc(file)
+	if err != nil || superblock.SMagic != EXT2_MAGIC {
+		return fmt.Errorf("la partición %s no tiene un sistema de archivos EXT2", id)
+	}
+
+	// 2. Buscar lo que se renombra y su carpeta padre
+	parentPath := filepath.Dir(cleanPath)
+	oldName := filepath.Base(cleanPath)
+	_, parentInode, err := FindInodeByPath(file, startByte, superblock, parentPath)
+	if err != nil {
+		return fmt.Errorf("no existe la carpeta '%s': %v", parentPath, err)
+	}
+	inodeNum, _, err := FindInodeByPath(file, startByte, superblock, cleanPath)
+	if err != nil {
+		return fmt.Errorf("no existe '%s'", cleanPath)
+	}
+	if inodeNum == 3 {
+		return fmt.Errorf("no se permite renombrar users.txt")
+	}
+	if err := CheckFilePermissions(parentInode, PERM_WRITE); err != nil {
+		return fmt.Errorf("error de permisos en '%s': %v", parentPath, err)
+	}
+
+	// 3. Leer las entradas de la carpeta padre: el nombre nuevo no puede estar en uso
+	dataBlocks, _, err := inodeBlocks(file, startByte, superblock, parentInode)
+	if err != nil {
+		return err
+	}
+	dirBlocks := make([]*DirectoryBlock, len(dataBlocks))
+	for i, blockNum := range dataBlocks {
+		blockPos := startByte + int64(superblock.SBlockStart) + int64(blockNum)*int64(superblock.SBlockSize)
+		if _, err := file.Seek(blockPos, 0); err != nil {
+			return fmt.Errorf("error al posicionarse en bloque %d: %v", blockNum, err)
+		}
+		dirBlocks[i], err = ReadDirectoryBlockFromDisc(file, int64(superblock.SBlockSize))
+		if err != nil {
+			return fmt.Errorf("error al leer bloque de carpeta %d: %v", blockNum, err)
+		}
+		if idx, _ := dirBlocks[i].FindEntry(newName); idx != -1 {
+			return fmt.Errorf("ya existe '%s' en la carpeta '%s'", newName, parentPath)
+		}
+	}
+
+	// 4. Reescribir el nombre de la entrada
+	for i, dirBlock := range dirBlocks {
+		idx, entryInode := dirBlock.FindEntry(oldName)
+		if idx == -1 || entryInode != int32(inodeNum) {
+			continue
+		}
+		if err := dirBlock.SetEntry(idx, newName, entryInode); err != nil {
+			return err
+		}
+
+		blockPos := startByte + int64(superblock.SBlockStart) + int64(dataBlocks[i])*int64(superblock.SBlockSize)
+		if _, err := file.Seek(blockPos, 0); err != nil {
+			return fmt.Errorf("error al posicionarse en bloque %d: %v", dataBlocks[i], err)
+		}
+		if err := writeDirectoryBlockToDisc(file, dirBlock); err != nil {
+			return fmt.Errorf("error al escribir bloque de carpeta %d: %v", dataBlocks[i], err)
+		}
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("error al sincronizar con el disco: %v", err)
+		}
+
+		fmt.Printf("Debug: '%s' renombrado a '%s' en %s (inodo %d)\n", cleanPath, newName, id, inodeNum)
+		return nil
+	}
+	return fmt.Errorf("no se encontró la entrada '%s' en la carpeta padre", oldName)
+}
@@ -1,6 +1,7 @@
 package DiskManager
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -30,7 +31,8 @@ type SuperBlock struct {
 	SBmBlockStart    int32     // Inicio bitmap bloques: 4 bytes, offset (cambio: uint32 para offsets grandes)
 	SInodeStart      int32     // Inicio tabla inodos: 4 bytes, offset (cambio: uint32 para offsets grandes)
 	SBlockStart      int32     // Inicio tabla bloques: 4 bytes, offset (cambio: uint32 para offsets grandes)
-	SPadding         [808]byte // Padding: 808 bytes para completar 1024 bytes exactos
+	SLastCheck       time.Time // Última revisión: se guarda como int64 (8 bytes), la fija mkfs
+	SPadding         [800]byte // Padding: 800 bytes para completar 1024 bytes exactos
 	// Ajustar SPadding si cambian otros campos para mantener 1024 bytes totales
 }
 
@@ -65,6 +67,7 @@ func NewSuperBlock(
 		SBmBlockStart:    bmBlockStart,
 		SInodeStart:      inodeStart,
 		SBlockStart:      blockStart,
+		SLastCheck:       now, // Un sistema recién formateado cuenta como revisado
 	}
 
 	// Resetear el padding
@@ -86,6 +89,22 @@ func (sb *SuperBlock) UpdateUnmountInfo() {
 	sb.SUmtime = time.Now()
 }
 
+// CheckRecommendation indica por qué conviene revisar el sistema de archivos, como lo
+// hace ext2 al montar: se alcanzó el máximo de montajes o pasó el intervalo máximo desde
+// la última revisión. Un límite <= 0 desactiva esa condición. Retorna "" si no hace falta.
+func (sb *SuperBlock) CheckRecommendation(maxMountCount int32, maxInterval time.Duration) string {
+	if maxMountCount > 0 && sb.SMntCount >= maxMountCount {
+		return fmt.Sprintf("el sistema de archivos se ha montado %d veces (máximo %d), se recomienda revisarlo",
+			sb.SMntCount, maxMountCount)
+	}
+	// Los sistemas formateados antes de guardar la fecha de revisión no la tienen
+	if maxInterval > 0 && sb.SLastCheck.Unix() > 0 && time.Since(sb.SLastCheck) > maxInterval {
+		return fmt.Sprintf("no se revisa el sistema de archivos desde %s, se recomienda revisarlo",
+			sb.SLastCheck.Format("2006-01-02 15:04:05"))
+	}
+	return ""
+}
+
 // AllocateInode marca un inodo como utilizado y actualiza contadores
 func (sb *SuperBlock) AllocateInode() {
 	if sb.SFreeInodesCount > 0 {
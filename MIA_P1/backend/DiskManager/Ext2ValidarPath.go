@@ -16,18 +16,12 @@ func ValidateEXT2Path(id string, path string) (bool, string, error) {
 	}
 
 	// 2. Abrir el disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, "", fmt.Errorf("Error al abrir el disco: %s", err)
 	}
 	defer file.Close()
 
-	// 3. Obtener detalles de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, "", fmt.Errorf("Error al obtener detalles de la partición: %s", err)
-	}
-
 	// 4. Leer el superbloque
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -0,0 +1,310 @@
+package DiskManager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EXT3_FILESYSTEM_TYPE identifica un sistema EXT3: el mismo EXT2 con un journal después del superbloque
+const EXT3_FILESYSTEM_TYPE = 3
+
+// Tamaños de los campos de una entrada del journal
+const (
+	JOURNAL_ENTRY_SIZE     = 256 // Tamaño exacto de una entrada serializada
+	JOURNAL_OPERATION_SIZE = 10
+	JOURNAL_PATH_SIZE      = 100
+	JOURNAL_CONTENT_SIZE   = 100
+	JOURNAL_NAME_SIZE      = 10 // Igual al máximo de usuarios y grupos
+)
+
+// Operaciones que se registran en el journal
+const (
+	JOURNAL_MKDIR     = "mkdir"
+	JOURNAL_MKFILE    = "mkfile"
+	JOURNAL_WRITE     = "write"
+	JOURNAL_APPEND    = "append"
+	JOURNAL_OVERWRITE = "overwrite"
+	JOURNAL_BADBLOCK  = "badblock"
+	JOURNAL_REMOVE    = "remove"
+	JOURNAL_RENAME    = "rename"
+	// JOURNAL_CONTINUATION marca una entrada que sigue el contenido de la anterior
+	JOURNAL_CONTINUATION = "+"
+)
+
+// Journal es una entrada del journal de EXT3. Si el contenido de una operación no cabe en
+// una entrada, sigue en las entradas siguientes marcadas con JOURNAL_CONTINUATION.
+type Journal struct {
+	JCount     int32                        // Número de la entrada desde 1, 0 si está libre
+	JOperation [JOURNAL_OPERATION_SIZE]byte // Operación (mkdir, mkfile, write...)
+	JPath      [JOURNAL_PATH_SIZE]byte      // Ruta afectada
+	JSize      int32                        // Bytes usados de JContent
+	JContent   [JOURNAL_CONTENT_SIZE]byte   // Contenido escrito o parámetro de la operación
+	JOwner     [JOURNAL_NAME_SIZE]byte      // Usuario propietario de lo que se crea
+	JGroup     [JOURNAL_NAME_SIZE]byte      // Grupo propietario de lo que se crea
+	JPerms     [3]byte                      // Permisos UGO de lo que se crea
+	JDate      int64                        // Timestamp Unix de la operación
+	JPadding   [7]byte                      // Padding para completar JOURNAL_ENTRY_SIZE
+}
+
+// journalOp es una operación a registrar antes de aplicarla
+type journalOp struct {
+	operation string
+	path      string
+	content   string
+	owner     string
+	group     string
+	perms     []byte
+}
+
+// journalActive guarda las particiones con una operación registrada en curso. Las
+// operaciones que esta dispare (como crear los directorios padres) ya quedan cubiertas
+// por la entrada de la operación original y no se registran otra vez.
+var (
+	journalActive   = make(map[string]bool)
+	journalActiveMu sync.Mutex
+)
+
+// FilesystemName retorna el nombre del sistema de archivos según el tipo del superbloque
+func FilesystemName(fsType int32) string {
+	if fsType == EXT3_FILESYSTEM_TYPE {
+		return "EXT3"
+	}
+	return "EXT2"
+}
+
+// journalCapacity retorna cuántas entradas caben entre el superbloque y el bitmap de inodos
+func journalCapacity(sb *SuperBlock) int {
+	if sb.SFilesystemType != EXT3_FILESYSTEM_TYPE {
+		return 0
+	}
+	return int(sb.SBmInodeStart-SUPERBLOCK_SIZE) / JOURNAL_ENTRY_SIZE
+}
+
+// readJournal lee todas las entradas del journal, libres incluidas
+func readJournal(file PartitionFile, startByte int64, sb *SuperBlock) ([]Journal, error) {
+	entries := make([]Journal, journalCapacity(sb))
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	if _, err := file.Seek(startByte+SUPERBLOCK_SIZE, 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor para el journal: %v", err)
+	}
+	if err := binary.Read(file, binary.LittleEndian, entries); err != nil {
+		return nil, fmt.Errorf("error leyendo el journal: %v", err)
+	}
+	return entries, nil
+}
+
+// writeJournalEntries escribe entries desde la posición index del journal
+func writeJournalEntries(file PartitionFile, startByte int64, index int, entries []Journal) error {
+	if _, err := file.Seek(startByte+SUPERBLOCK_SIZE+int64(index)*JOURNAL_ENTRY_SIZE, 0); err != nil {
+		return fmt.Errorf("error posicionando cursor para el journal: %v", err)
+	}
+	if err := binary.Write(file, binary.LittleEndian, entries); err != nil {
+		return fmt.Errorf("error escribiendo el journal: %v", err)
+	}
+	return file.Sync()
+}
+
+// newJournalEntries arma las entradas de una operación, repartiendo el contenido entre
+// la entrada principal y las de continuación que hagan falta
+func newJournalEntries(op journalOp, date time.Time) []Journal {
+	entry := Journal{JDate: date.Unix()}
+	copy(entry.JOperation[:], op.operation)
+	copy(entry.JPath[:], op.path)
+	copy(entry.JOwner[:], op.owner)
+	copy(entry.JGroup[:], op.group)
+	copy(entry.JPerms[:], op.perms)
+
+	content := []byte(op.content)
+	entry.JSize = int32(copy(entry.JContent[:], content))
+	entries := []Journal{entry}
+	for content = content[entry.JSize:]; len(content) > 0; {
+		next := Journal{JDate: entry.JDate}
+		copy(next.JOperation[:], JOURNAL_CONTINUATION)
+		next.JSize = int32(copy(next.JContent[:], content))
+		content = content[next.JSize:]
+		entries = append(entries, next)
+	}
+	return entries
+}
+
+// recordJournal agrega la operación al final del journal. Retorna la posición de la primera
+// entrada y cuántas ocupó; 0 entradas si la partición no tiene journal.
+func recordJournal(id string, op journalOp) (int, int, error) {
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil {
+		return 0, 0, err
+	}
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error al abrir disco: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return 0, 0, fmt.Errorf("error al posicionarse para leer superbloque: %v", err)
+	}
+	superblock, err := ReadSuperBlockFromDisc(file)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error al leer superbloque: %v", err)
+	}
+	if superblock.SMagic != EXT2_MAGIC || superblock.SFilesystemType != EXT3_FILESYSTEM_TYPE {
+		return 0, 0, nil
+	}
+	if len(op.path) > JOURNAL_PATH_SIZE {
+		return 0, 0, fmt.Errorf("la ruta '%s' supera los %d caracteres que admite el journal", op.path, JOURNAL_PATH_SIZE)
+	}
+
+	journal, err := readJournal(file, startByte, superblock)
+	if err != nil {
+		return 0, 0, err
+	}
+	used := 0
+	for used < len(journal) && journal[used].JCount != 0 {
+		used++
+	}
+
+	entries := newJournalEntries(op, time.Now())
+	if used+len(entries) > len(journal) {
+		return 0, 0, fmt.Errorf("el journal de la partición %s está lleno (%d entradas), no se puede registrar la operación", id, len(journal))
+	}
+	for i := range entries {
+		entries[i].JCount = int32(used + i + 1)
+	}
+	if err := writeJournalEntries(file, startByte, used, entries); err != nil {
+		return 0, 0, err
+	}
+
+	fmt.Printf("Debug: Journal de %s: %s '%s' registrado en la entrada %d (%d entradas)\n",
+		id, op.operation, op.path, used+1, len(entries))
+	return used, len(entries), nil
+}
+
+// discardJournal libera las entradas de una operación que no se pudo aplicar
+func discardJournal(id string, index, count int) {
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err == nil {
+		var file PartitionFile
+		var startByte int64
+		file, startByte, _, err = OpenMountedPartition(mountedPartition, os.O_RDWR)
+		if err == nil {
+			err = writeJournalEntries(file, startByte, index, make([]Journal, count))
+			file.Close()
+		}
+	}
+	if err != nil {
+		fmt.Printf("Advertencia: no se pudo descartar la entrada %d del journal de %s: %v\n", index+1, id, err)
+	}
+}
+
+// journalOperation registra op en el journal de una partición EXT3 antes de aplicarla con
+// apply. Si apply falla la entrada se descarta, así el journal solo tiene operaciones
+// aplicadas. En EXT2, en solo lectura o dentro de otra operación registrada solo se ejecuta apply.
+func journalOperation(id string, op journalOp, apply func() error) error {
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil || mountedPartition.ReadOnly {
+		return apply()
+	}
+	key := mountedPartition.ID
+
+	journalActiveMu.Lock()
+	nested := journalActive[key]
+	journalActiveMu.Unlock()
+	if nested {
+		return apply()
+	}
+
+	index, count, err := recordJournal(key, op)
+	if err != nil {
+		return err
+	}
+
+	journalActiveMu.Lock()
+	journalActive[key] = true
+	journalActiveMu.Unlock()
+	defer func() {
+		journalActiveMu.Lock()
+		delete(journalActive, key)
+		journalActiveMu.Unlock()
+	}()
+
+	if err := apply(); err != nil {
+		if count > 0 {
+			discardJournal(key, index, count)
+		}
+		return err
+	}
+	return nil
+}
+
+// JournalOperation es una operación del journal con el contenido de sus entradas de
+// continuación ya unido
+type JournalOperation struct {
+	Count     int32 // Número de la primera entrada
+	Entries   int   // Entradas que ocupa, contando las de continuación
+	Operation string
+	Path      string
+	Content   string
+	Owner     string
+	Group     string
+	Perms     []byte
+	Date      time.Time
+}
+
+// journalOperations agrupa las entradas usadas del journal en operaciones
+func journalOperations(entries []Journal) []JournalOperation {
+	var ops []JournalOperation
+	for _, entry := range entries {
+		if entry.JCount == 0 {
+			break
+		}
+		content := string(entry.JContent[:entry.JSize])
+		operation := journalText(entry.JOperation[:])
+		if operation == JOURNAL_CONTINUATION && len(ops) > 0 {
+			ops[len(ops)-1].Content += content
+			ops[len(ops)-1].Entries++
+			continue
+		}
+		ops = append(ops, JournalOperation{
+			Count:     entry.JCount,
+			Entries:   1,
+			Operation: operation,
+			Path:      journalText(entry.JPath[:]),
+			Content:   content,
+			Owner:     journalText(entry.JOwner[:]),
+			Group:     journalText(entry.JGroup[:]),
+			Perms:     append([]byte(nil), entry.JPerms[:]...),
+			Date:      time.Unix(entry.JDate, 0),
+		})
+	}
+	return ops
+}
+
+// recordInitialBadBlocks registra en el journal recién formateado los bloques dañados
+// indicados en mkfs, para que una recuperación también los vuelva a marcar
+func recordInitialBadBlocks(file PartitionFile, startByte int64, sb *SuperBlock, badBlocks []int32) error {
+	var entries []Journal
+	now := time.Now()
+	for _, blockNum := range badBlocks {
+		op := journalOp{operation: JOURNAL_BADBLOCK, content: strconv.Itoa(int(blockNum))}
+		entries = append(entries, newJournalEntries(op, now)...)
+	}
+	if len(entries) > journalCapacity(sb) {
+		return fmt.Errorf("el journal no tiene espacio para %d bloques dañados", len(badBlocks))
+	}
+	for i := range entries {
+		entries[i].JCount = int32(i + 1)
+	}
+	return writeJournalEntries(file, startByte, 0, entries)
+}
+
+// journalText convierte un campo de la entrada en texto, sin los ceros del final
+func journalText(field []byte) string {
+	return string(bytes.TrimRight(field, "\x00"))
+}
@@ -0,0 +1,258 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestPartition crea un disco de 1 MB con una partición primaria de 300 KB, la monta y
+// la formatea con el sistema fsType. Retorna el ID de montaje.
+func newTestPartition(t *testing.T, fsType int32) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv(DATA_DIR_ENV, filepath.Join(dir, "estado"))
+	mounted := utils.MountedPartitions
+	utils.MountedPartitions = nil
+	t.Cleanup(func() { utils.MountedPartitions = mounted })
+
+	path := filepath.Join(dir, "disco.mia")
+	if err := CreateDisk(utils.DiskConfig{Size: 1, Unit: "M", Fit: "FF", Path: path, Alloc: ALLOC_SPARSE}); err != nil {
+		t.Fatalf("CreateDisk: %v", err)
+	}
+	pm, err := NewPartitionManager(path)
+	if err != nil {
+		t.Fatalf("NewPartitionManager: %v", err)
+	}
+	partition := NewPartition()
+	partition.Size = 300
+	copy(partition.Name[:], "p1")
+	if err := pm.CreatePartition(&partition, "K"); err != nil {
+		t.Fatalf("CreatePartition: %v", err)
+	}
+
+	id, _, err := MountPartition(path, "p1", false)
+	if err != nil {
+		t.Fatalf("MountPartition: %v", err)
+	}
+	if ok, mensaje := FormatearParticion(id, "full", fsType, nil, ""); !ok {
+		t.Fatalf("FormatearParticion: %s", mensaje)
+	}
+	return id
+}
+
+// readTestJournal lee todas las entradas del journal de la partición montada
+func readTestJournal(t *testing.T, id string) []Journal {
+	t.Helper()
+	mp, err := FindMountedPartitionById(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, startByte, _, err := OpenMountedPartition(mp, os.O_RDONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(startByte, 0); err != nil {
+		t.Fatal(err)
+	}
+	sb, err := ReadSuperBlockFromDisc(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := readJournal(file, startByte, sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+// usedJournalEntries cuenta las entradas ocupadas al inicio del journal
+func usedJournalEntries(entries []Journal) int {
+	used := 0
+	for used < len(entries) && entries[used].JCount != 0 {
+		used++
+	}
+	return used
+}
+
+// joinedContent junta el contenido de la entrada index y de sus continuaciones
+func joinedContent(entries []Journal, index int) string {
+	var content strings.Builder
+	content.Write(entries[index].JContent[:entries[index].JSize])
+	for i := index + 1; i < len(entries) && entryText(entries[i].JOperation[:]) == JOURNAL_CONTINUATION; i++ {
+		content.Write(entries[i].JContent[:entries[i].JSize])
+	}
+	return content.String()
+}
+
+// entryText convierte un campo de la entrada en texto, sin los bytes nulos del final
+func entryText(b []byte) string {
+	return strings.TrimRight(string(b), "\x00")
+}
+
+func TestNewJournalEntries(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantEntries int
+	}{
+		{"sin contenido", "", 1},
+		{"una entrada", strings.Repeat("a", JOURNAL_CONTENT_SIZE), 1},
+		{"un byte de más", strings.Repeat("b", JOURNAL_CONTENT_SIZE+1), 2},
+		{"varias continuaciones", strings.Repeat("0123456789", 25), 3},
+	}
+
+	date := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := journalOp{operation: JOURNAL_MKFILE, path: "/home/a.txt", content: tt.content, owner: "root", group: "root", perms: []byte{6, 6, 4}}
+			entries := newJournalEntries(op, date)
+			if len(entries) != tt.wantEntries {
+				t.Fatalf("%d entradas, se esperaban %d", len(entries), tt.wantEntries)
+			}
+
+			first := entries[0]
+			if entryText(first.JOperation[:]) != JOURNAL_MKFILE || entryText(first.JPath[:]) != op.path ||
+				entryText(first.JOwner[:]) != "root" || first.JPerms != [3]byte{6, 6, 4} {
+				t.Errorf("entrada principal inesperada: %+v", first)
+			}
+			for i, entry := range entries {
+				if i > 0 && entryText(entry.JOperation[:]) != JOURNAL_CONTINUATION {
+					t.Errorf("la entrada %d es %q, se esperaba una continuación", i, entryText(entry.JOperation[:]))
+				}
+				if entry.JDate != date.Unix() {
+					t.Errorf("la entrada %d tiene fecha %d, se esperaba %d", i, entry.JDate, date.Unix())
+				}
+			}
+			if got := joinedContent(entries, 0); got != tt.content {
+				t.Errorf("el contenido reconstruido tiene %d bytes, se esperaban %d", len(got), len(tt.content))
+			}
+		})
+	}
+}
+
+func TestJournalCapacity(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsType  int32
+		bmStart int32
+		want    int
+	}{
+		{"EXT2", EXT2_FILESYSTEM_TYPE, SUPERBLOCK_SIZE, 0},
+		{"EXT3 sin espacio", EXT3_FILESYSTEM_TYPE, SUPERBLOCK_SIZE, 0},
+		{"EXT3 exacto", EXT3_FILESYSTEM_TYPE, SUPERBLOCK_SIZE + 10*JOURNAL_ENTRY_SIZE, 10},
+		{"EXT3 con sobrante", EXT3_FILESYSTEM_TYPE, SUPERBLOCK_SIZE + 10*JOURNAL_ENTRY_SIZE + 100, 10},
+	}
+	for _, tt := range tests {
+		sb := &SuperBlock{SFilesystemType: tt.fsType, SBmInodeStart: tt.bmStart}
+		if got := journalCapacity(sb); got != tt.want {
+			t.Errorf("%s: journalCapacity = %d, se esperaba %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordJournal(t *testing.T) {
+	id := newTestPartition(t, EXT3_FILESYSTEM_TYPE)
+	base := usedJournalEntries(readTestJournal(t, id))
+
+	ops := []struct {
+		op        journalOp
+		wantCount int
+	}{
+		{journalOp{operation: JOURNAL_MKDIR, path: "/home", owner: "root", group: "root", perms: []byte{6, 6, 4}}, 1},
+		{journalOp{operation: JOURNAL_MKFILE, path: "/home/a.txt", content: strings.Repeat("x", 250), owner: "root", group: "root", perms: []byte{6, 6, 4}}, 3},
+		{journalOp{operation: JOURNAL_APPEND, path: "/home/a.txt", content: "fin"}, 1},
+	}
+
+	next := base
+	for _, tt := range ops {
+		index, count, err := recordJournal(id, tt.op)
+		if err != nil {
+			t.Fatalf("recordJournal(%s): %v", tt.op.operation, err)
+		}
+		if index != next || count != tt.wantCount {
+			t.Errorf("recordJournal(%s) = (%d, %d), se esperaba (%d, %d)", tt.op.operation, index, count, next, tt.wantCount)
+		}
+		next += count
+	}
+
+	entries := readTestJournal(t, id)
+	if used := usedJournalEntries(entries); used != next {
+		t.Fatalf("el journal tiene %d entradas ocupadas, se esperaban %d", used, next)
+	}
+	for i := 0; i < next; i++ {
+		if entries[i].JCount != int32(i+1) {
+			t.Errorf("la entrada %d tiene JCount %d", i, entries[i].JCount)
+		}
+	}
+	index := base
+	for _, tt := range ops {
+		if op := entryText(entries[index].JOperation[:]); op != tt.op.operation {
+			t.Errorf("la entrada %d es %q, se esperaba %q", index, op, tt.op.operation)
+		}
+		if got := joinedContent(entries, index); got != tt.op.content {
+			t.Errorf("la entrada %d guarda %q, se esperaba %q", index, got, tt.op.content)
+		}
+		index += tt.wantCount
+	}
+}
+
+func TestRecordJournalLimits(t *testing.T) {
+	t.Run("EXT2 sin journal", func(t *testing.T) {
+		id := newTestPartition(t, EXT2_FILESYSTEM_TYPE)
+		index, count, err := recordJournal(id, journalOp{operation: JOURNAL_MKDIR, path: "/home"})
+		if err != nil || index != 0 || count != 0 {
+			t.Errorf("recordJournal = (%d, %d, %v), se esperaba (0, 0, nil)", index, count, err)
+		}
+	})
+
+	t.Run("ruta muy larga", func(t *testing.T) {
+		id := newTestPartition(t, EXT3_FILESYSTEM_TYPE)
+		path := "/" + strings.Repeat("d", JOURNAL_PATH_SIZE)
+		if _, _, err := recordJournal(id, journalOp{operation: JOURNAL_MKDIR, path: path}); err == nil {
+			t.Errorf("recordJournal aceptó una ruta de %d caracteres", len(path))
+		}
+	})
+
+	t.Run("journal lleno", func(t *testing.T) {
+		id := newTestPartition(t, EXT3_FILESYSTEM_TYPE)
+		capacity := len(readTestJournal(t, id))
+		var err error
+		for i := 0; i <= capacity && err == nil; i++ {
+			_, _, err = recordJournal(id, journalOp{operation: JOURNAL_MKDIR, path: "/d"})
+		}
+		if err == nil || !strings.Contains(err.Error(), "lleno") {
+			t.Errorf("se esperaba el error de journal lleno, se obtuvo %v", err)
+		}
+		if used := usedJournalEntries(readTestJournal(t, id)); used != capacity {
+			t.Errorf("el journal tiene %d entradas ocupadas, se esperaban %d", used, capacity)
+		}
+	})
+}
+
+// Una operación que falla al aplicarse no deja entradas en el journal
+func TestJournalOperationDiscardsOnError(t *testing.T) {
+	id := newTestPartition(t, EXT3_FILESYSTEM_TYPE)
+	base := usedJournalEntries(readTestJournal(t, id))
+
+	applyErr := errors.New("falla al aplicar")
+	op := journalOp{operation: JOURNAL_MKFILE, path: "/a.txt", content: strings.Repeat("x", 150)}
+	if err := journalOperation(id, op, func() error { return applyErr }); err != applyErr {
+		t.Fatalf("journalOperation = %v, se esperaba %v", err, applyErr)
+	}
+	if used := usedJournalEntries(readTestJournal(t, id)); used != base {
+		t.Errorf("quedaron %d entradas ocupadas, se esperaban %d", used, base)
+	}
+
+	if err := journalOperation(id, op, func() error { return nil }); err != nil {
+		t.Fatalf("journalOperation: %v", err)
+	}
+	if used := usedJournalEntries(readTestJournal(t, id)); used != base+2 {
+		t.Errorf("quedaron %d entradas ocupadas, se esperaban %d", used, base+2)
+	}
+}
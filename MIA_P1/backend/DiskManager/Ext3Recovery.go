@@ -0,0 +1,198 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/common"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// openJournaledPartition abre una partición montada con EXT3 y lee su superbloque y su journal
+func openJournaledPartition(id string, flag int) (PartitionFile, int64, *SuperBlock, []Journal, error) {
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil {
+		return nil, 0, nil, nil, err
+	}
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, flag)
+	if err != nil {
+		return nil, 0, nil, nil, fmt.Errorf("error al abrir disco: %v", err)
+	}
+
+	if _, err := file.Seek(startByte, 0); err != nil {
+		file.Close()
+		return nil, 0, nil, nil, fmt.Errorf("error al posicionarse para leer superbloque: %v", err)
+	}
+	superblock, err := ReadSuperBlockFromDisc(file)
+	if err != nil {
+		file.Close()
+		return nil, 0, nil, nil, fmt.Errorf("error al leer superbloque: %v", err)
+	}
+	if superblock.SMagic != EXT2_MAGIC || superblock.SFilesystemType != EXT3_FILESYSTEM_TYPE {
+		file.Close()
+		return nil, 0, nil, nil, fmt.Errorf("la partición %s no tiene un sistema de archivos EXT3 con journal", id)
+	}
+
+	journal, err := readJournal(file, startByte, superblock)
+	if err != nil {
+		file.Close()
+		return nil, 0, nil, nil, err
+	}
+	return file, startByte, superblock, journal, nil
+}
+
+// GetJournal retorna las operaciones registradas en el journal de una partición EXT3
+func GetJournal(id string) ([]JournalOperation, error) {
+	file, _, _, journal, err := openJournaledPartition(id, os.O_RDONLY)
+	if err != nil {
+		return nil, err
+	}
+	file.Close()
+	return journalOperations(journal), nil
+}
+
+// SimulateLoss simula una falla del disco: llena de ceros los bitmaps, la tabla de inodos
+// y los bloques de datos, y deja intactos el superbloque y el journal. Retorna los bytes borrados.
+func SimulateLoss(id string) (int64, error) {
+	if err := CheckWritable(id); err != nil {
+		return 0, err
+	}
+	file, startByte, superblock, _, err := openJournaledPartition(id, os.O_RDWR)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	lossStart := startByte + int64(superblock.SBmInodeStart)
+	lossEnd := startByte + int64(superblock.SBlockStart) + int64(superblock.SBlocksCount)*int64(superblock.SBlockSize)
+	zeroBuffer := make([]byte, CLONE_CHUNK)
+	for pos := lossStart; pos < lossEnd; pos += CLONE_CHUNK {
+		chunk := int64(CLONE_CHUNK)
+		if lossEnd-pos < chunk {
+			chunk = lossEnd - pos
+		}
+		if _, err := file.WriteAt(zeroBuffer[:chunk], pos); err != nil {
+			return 0, fmt.Errorf("error borrando la partición: %v", err)
+		}
+	}
+	if err := file.Sync(); err != nil {
+		return 0, err
+	}
+
+	fmt.Printf("Debug: Pérdida simulada en %s: %d bytes borrados desde %d\n", id, lossEnd-lossStart, lossStart)
+	return lossEnd - lossStart, nil
+}
+
+// RecoverFromJournal reconstruye el sistema de archivos de una partición EXT3: lo formatea
+// de nuevo conservando el journal y la información de montaje del superbloque, y vuelve a
+// aplicar cada operación del journal con las mismas funciones que la aplicaron la primera vez.
+// Retorna cuántas operaciones se aplicaron y la descripción de las que fallaron.
+func RecoverFromJournal(id string) (int, []string, error) {
+	if err := CheckWritable(id); err != nil {
+		return 0, nil, err
+	}
+	file, _, oldSuperblock, journal, err := openJournaledPartition(id, os.O_RDONLY)
+	if err != nil {
+		return 0, nil, err
+	}
+	file.Close()
+
+	// 1. Formatear de nuevo: deja la raíz y users.txt como los dejó mkfs
+	if ok, mensaje := FormatearParticion(id, "full", EXT3_FILESYSTEM_TYPE, nil, ""); !ok {
+		return 0, nil, fmt.Errorf("no se pudo reconstruir la partición: %s", mensaje)
+	}
+
+	// 2. Restaurar el journal y la información de montaje que mkfs reinició
+	file, startByte, superblock, _, err := openJournaledPartition(id, os.O_RDWR)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := writeJournalEntries(file, startByte, 0, journal); err != nil {
+		file.Close()
+		return 0, nil, err
+	}
+	superblock.SMtime = oldSuperblock.SMtime
+	superblock.SUmtime = oldSuperblock.SUmtime
+	superblock.SMntCount = oldSuperblock.SMntCount
+	if _, err := file.Seek(startByte, 0); err == nil {
+		err = writeSuperBlockToDisc(file, superblock)
+	}
+	file.Close()
+	if err != nil {
+		return 0, nil, fmt.Errorf("error al escribir superbloque: %v", err)
+	}
+
+	// 3. Volver a aplicar las operaciones sin registrarlas otra vez
+	mountedPartition, err := FindMountedPartitionById(id)
+	if err != nil {
+		return 0, nil, err
+	}
+	key := mountedPartition.ID
+	journalActiveMu.Lock()
+	journalActive[key] = true
+	journalActiveMu.Unlock()
+	defer func() {
+		journalActiveMu.Lock()
+		delete(journalActive, key)
+		journalActiveMu.Unlock()
+	}()
+
+	applied := 0
+	var failed []string
+	for _, op := range journalOperations(journal) {
+		if err := replayJournalOperation(key, op); err != nil {
+			fmt.Printf("Advertencia: no se pudo aplicar la entrada %d del journal (%s %s): %v\n", op.Count, op.Operation, op.Path, err)
+			failed = append(failed, fmt.Sprintf("entrada %d (%s %s): %v", op.Count, op.Operation, op.Path, err))
+			continue
+		}
+		applied++
+	}
+
+	fmt.Printf("Debug: Partición %s recuperada desde el journal: %d operaciones aplicadas, %d fallidas\n", id, applied, len(failed))
+	return applied, failed, nil
+}
+
+// replayJournalOperation aplica una operación del journal
+func replayJournalOperation(id string, op JournalOperation) error {
+	switch op.Operation {
+	case JOURNAL_MKDIR:
+		return CreateEXT2Directory(id, op.Path, op.Owner, op.Group, op.Perms)
+	case JOURNAL_MKFILE:
+		return CreateEXT2File(id, op.Path, op.Content, op.Owner, op.Group, op.Perms)
+	case JOURNAL_WRITE:
+		_, err := EXT2FileOperation(id, op.Path, FILE_WRITE, op.Content)
+		return err
+	case JOURNAL_APPEND:
+		_, err := EXT2FileOperation(id, op.Path, FILE_APPEND, op.Content)
+		return err
+	case JOURNAL_OVERWRITE:
+		return OverwriteEXT2File(id, op.Path, op.Content)
+	case JOURNAL_BADBLOCK:
+		blockNum, err := strconv.Atoi(op.Content)
+		if err != nil {
+			return fmt.Errorf("bloque inválido '%s'", op.Content)
+		}
+		_, err = MarkBadBlock(id, int32(blockNum))
+		return err
+	case JOURNAL_REMOVE:
+		return replayAsUser(id, op, func() error {
+			_, err := RemoveEXT2Path(id, op.Path, op.Owner, op.Group)
+			return err
+		})
+	case JOURNAL_RENAME:
+		return replayAsUser(id, op, func() error {
+			return RenameEXT2Path(id, op.Path, op.Content, op.Owner, op.Group)
+		})
+	default:
+		return fmt.Errorf("operación desconocida '%s'", op.Operation)
+	}
+}
+
+// replayAsUser aplica una operación con el usuario que la hizo como usuario activo, para
+// que la verificación de permisos conserve lo mismo que la primera vez
+func replayAsUser(id string, op JournalOperation, apply func() error) error {
+	userID, groupID := common.ActiveUserID, common.ActiveGroupID
+	defer common.SetActiveUser(userID, groupID)
+
+	common.SetActiveUser(getUserIdFromName(id, op.Owner), getGroupIdFromName(id, op.Group))
+	return apply()
+}
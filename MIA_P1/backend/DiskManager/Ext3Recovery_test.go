@@ -0,0 +1,184 @@
+package DiskManager
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testJournalEntry arma una entrada usada del journal
+func testJournalEntry(count int32, operation, path, content string) Journal {
+	entry := Journal{JCount: count, JSize: int32(len(content))}
+	copy(entry.JOperation[:], operation)
+	copy(entry.JPath[:], path)
+	copy(entry.JContent[:], content)
+	return entry
+}
+
+func TestJournalOperations(t *testing.T) {
+	type op struct {
+		count     int32
+		entries   int
+		operation string
+		path      string
+		content   string
+	}
+	tests := []struct {
+		name    string
+		entries []Journal
+		want    []op
+	}{
+		{"journal vacío", make([]Journal, 3), nil},
+		{
+			name: "una operación por entrada",
+			entries: []Journal{
+				testJournalEntry(1, JOURNAL_MKDIR, "/home", ""),
+				testJournalEntry(2, JOURNAL_MKFILE, "/home/a.txt", "hola"),
+				{},
+			},
+			want: []op{
+				{1, 1, JOURNAL_MKDIR, "/home", ""},
+				{2, 1, JOURNAL_MKFILE, "/home/a.txt", "hola"},
+			},
+		},
+		{
+			name: "continuaciones",
+			entries: []Journal{
+				testJournalEntry(1, JOURNAL_MKFILE, "/a.txt", "aaa"),
+				testJournalEntry(2, JOURNAL_CONTINUATION, "", "bbb"),
+				testJournalEntry(3, JOURNAL_CONTINUATION, "", "ccc"),
+				testJournalEntry(4, JOURNAL_APPEND, "/a.txt", "ddd"),
+				testJournalEntry(5, JOURNAL_CONTINUATION, "", "eee"),
+			},
+			want: []op{
+				{1, 3, JOURNAL_MKFILE, "/a.txt", "aaabbbccc"},
+				{4, 2, JOURNAL_APPEND, "/a.txt", "dddeee"},
+			},
+		},
+		{
+			name: "se detiene en la primera entrada libre",
+			entries: []Journal{
+				testJournalEntry(1, JOURNAL_MKDIR, "/a", ""),
+				{},
+				testJournalEntry(3, JOURNAL_MKDIR, "/b", ""),
+			},
+			want: []op{{1, 1, JOURNAL_MKDIR, "/a", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []op
+			for _, o := range journalOperations(tt.entries) {
+				got = append(got, op{o.Count, o.Entries, o.Operation, o.Path, o.Content})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("journalOperations = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fileSystemSnapshot describe cada archivo y directorio de la partición por su ruta, sin
+// las fechas, que cambian al recuperar
+func fileSystemSnapshot(t *testing.T, id string) (map[string]string, error) {
+	t.Helper()
+	info, err := GetFileSystemStructure(id)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(map[string]string)
+	var walk func(node *FSNode)
+	walk = func(node *FSNode) {
+		snapshot[node.Path] = fmt.Sprintf("%s inodo=%d %s %s:%s %d %q",
+			node.Type, node.InodeNum, node.Permissions, node.Owner, node.Group, node.Size, node.Content)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	if info.RootNode != nil {
+		walk(info.RootNode)
+	}
+	return snapshot, nil
+}
+
+// Round trip de EXT3: las operaciones registradas en el journal reconstruyen el mismo
+// árbol después de una pérdida
+func TestRecoverFromJournal(t *testing.T) {
+	id := newTestPartition(t, EXT3_FILESYSTEM_TYPE)
+	perms := []byte{6, 6, 4}
+	long := strings.Repeat("contenido largo ", 20) // ocupa varias entradas del journal
+
+	steps := []struct {
+		name  string
+		apply func() error
+	}{
+		{"mkdir /home", func() error { return CreateEXT2Directory(id, "/home", "root", "root", perms) }},
+		{"mkdir /home/user", func() error { return CreateEXT2Directory(id, "/home/user", "root", "root", perms) }},
+		{"mkfile /home/user/a.txt", func() error { return CreateEXT2File(id, "/home/user/a.txt", long, "root", "root", perms) }},
+		{"mkfile /b.txt", func() error { return CreateEXT2File(id, "/b.txt", "corto", "root", "root", perms) }},
+		{"append /b.txt", func() error {
+			_, err := EXT2FileOperation(id, "/b.txt", FILE_APPEND, " y mas")
+			return err
+		}},
+	}
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+
+	want, err := fileSystemSnapshot(t, id)
+	if err != nil {
+		t.Fatalf("GetFileSystemStructure: %v", err)
+	}
+	if !strings.Contains(want["/home/user/a.txt"], long) || !strings.Contains(want["/b.txt"], "corto y mas") {
+		t.Fatalf("el árbol no tiene los archivos creados: %v", want)
+	}
+	ops, err := GetJournal(id)
+	if err != nil {
+		t.Fatalf("GetJournal: %v", err)
+	}
+
+	if _, err := SimulateLoss(id); err != nil {
+		t.Fatalf("SimulateLoss: %v", err)
+	}
+	if lost, err := fileSystemSnapshot(t, id); err == nil && reflect.DeepEqual(lost, want) {
+		t.Fatalf("el árbol no cambió después de la pérdida")
+	}
+
+	applied, failed, err := RecoverFromJournal(id)
+	if err != nil {
+		t.Fatalf("RecoverFromJournal: %v", err)
+	}
+	if applied != len(ops) || len(failed) != 0 {
+		t.Errorf("RecoverFromJournal aplicó %d de %d operaciones, fallidas: %v", applied, len(ops), failed)
+	}
+
+	got, err := fileSystemSnapshot(t, id)
+	if err != nil {
+		t.Fatalf("GetFileSystemStructure después de recuperar: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		for path := range want {
+			if got[path] != want[path] {
+				t.Errorf("%s: %s, se esperaba %s", path, got[path], want[path])
+			}
+		}
+		for path := range got {
+			if _, ok := want[path]; !ok {
+				t.Errorf("%s sobra después de recuperar", path)
+			}
+		}
+	}
+
+	// El journal se conserva y no se duplica al volver a aplicarlo
+	after, err := GetJournal(id)
+	if err != nil {
+		t.Fatalf("GetJournal después de recuperar: %v", err)
+	}
+	if len(after) != len(ops) {
+		t.Errorf("el journal tiene %d operaciones después de recuperar, se esperaban %d", len(after), len(ops))
+	}
+}
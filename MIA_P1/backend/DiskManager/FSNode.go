@@ -49,18 +49,19 @@ func GetFileSystemStructure(id string) (*FSInfo, error) {
 		return nil, fmt.Errorf("error: %s", err)
 	}
 
-	// 2. Abrir el archivo de disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDONLY, 0666)
+	// Lectura: candado compartido de los discos de la partición (también el del espejo)
+	unlock, err := LockMountedPartition(id, DISK_LOCK_SHARED)
 	if err != nil {
-		return nil, fmt.Errorf("error al abrir el disco: %s", err)
+		return nil, err
 	}
-	defer file.Close()
+	defer unlock()
 
-	// 3. Obtener inicio de la partición y leer superbloque
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
+	// 2. Abrir el archivo de disco
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDONLY)
 	if err != nil {
-		return nil, fmt.Errorf("error al obtener detalles de la partición: %s", err)
+		return nil, fmt.Errorf("error al abrir el disco: %s", err)
 	}
+	defer file.Close()
 
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -213,7 +214,7 @@ func GetFileSystemStructure(id string) (*FSInfo, error) {
 }
 
 // Función auxiliar para leer las entradas de un directorio
-func readDirectoryEntries(file *os.File, inode *Inode, startByte int64, blockSize int32, blocksStart int64) []struct {
+func readDirectoryEntries(file PartitionFile, inode *Inode, startByte int64, blockSize int32, blocksStart int64) []struct {
 	Name     string
 	InodeNum int
 } {
@@ -284,7 +285,7 @@ func readDirectoryEntries(file *os.File, inode *Inode, startByte int64, blockSiz
 
 // NOMBRE CAMBIADO para evitar conflicto con otra función existente
 // Función para leer el contenido de un archivo
-func readNodeFileContent(file *os.File, inode *Inode, startByte int64, blockSize int32, blocksStart int64) string {
+func readNodeFileContent(file PartitionFile, inode *Inode, startByte int64, blockSize int32, blocksStart int64) string {
 	var content strings.Builder
 
 	// Leer bloques directos
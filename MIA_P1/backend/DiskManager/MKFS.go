@@ -11,27 +11,38 @@ import (
 	"time"
 )
 
-// FormatearParticion formatea una partición con el sistema de archivos EXT2
-func FormatearParticion(id, formatType string) (bool, string) {
+// FormatearParticion formatea una partición con el sistema de archivos EXT2, o EXT3 si
+// fsType es EXT3_FILESYSTEM_TYPE (con el journal después del superbloque).
+// Los bloques de badBlocks quedan en la lista de bloques dañados desde el inicio.
+// Con passphrase la partición se cifra antes de formatearla.
+func FormatearParticion(id, formatType string, fsType int32, badBlocks []int32, passphrase string) (bool, string) {
 	// 1. Verificar que exista el ID de la partición montada
 	mountedPartition, err := FindMountedPartitionById(id)
 	if err != nil {
 		return false, fmt.Sprintf("Error: %s", err)
 	}
+	if mountedPartition.ReadOnly {
+		return false, fmt.Sprintf("Error: la partición %s está montada en modo solo lectura, no se puede formatear", id)
+	}
+	if passphrase != "" {
+		if mountedPartition.PartitionType == PARTITION_VOLUME {
+			return false, fmt.Sprintf("Error: %s es un volumen, no se puede cifrar", id)
+		}
+		if mountedPartition.MirrorPath != "" {
+			return false, fmt.Sprintf("Error: la partición %s está montada con espejo, no se puede cifrar", id)
+		}
+		if err := EncryptPartition(mountedPartition.DiskPath, mountedPartition.PartitionName, passphrase); err != nil {
+			return false, fmt.Sprintf("Error al cifrar la partición: %s", err)
+		}
+	}
 
 	// 2. Verificar que el disco existe físicamente
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, size, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, fmt.Sprintf("Error al abrir el disco: %s", err)
 	}
 	defer file.Close()
 
-	// 3. Buscar los detalles de la partición en el disco
-	startByte, size, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, fmt.Sprintf("Error al obtener detalles de la partición: %s", err)
-	}
-
 	fmt.Printf("Inicializando partición con ceros desde %d, tamaño %d bytes...\n", startByte, size)
 	zeroBuffer := make([]byte, 8192) // Buffer de 8KB para eficiencia
 	_, err = file.Seek(startByte, 0)
@@ -60,27 +71,34 @@ func FormatearParticion(id, formatType string) (bool, string) {
 		return false, fmt.Sprintf("Error al posicionarse después de inicializar: %s", err)
 	}
 
-	// 4. Calcular el tamaño de las estructuras EXT2 para esta partición
+	// 4. Calcular el tamaño de las estructuras EXT2 (y el journal en EXT3) para esta partición
 	extInfo := CalculateEXT2Format(size)
+	if fsType == EXT3_FILESYSTEM_TYPE {
+		extInfo = CalculateEXT3Format(size)
+	}
 
 	// 5. Verificar que el formato sea válido para esta partición
 	if !ValidateEXT2Format(extInfo) {
-		return false, "Error: La partición es demasiado pequeña para formatear con EXT2"
+		return false, fmt.Sprintf("Error: La partición es demasiado pequeña para formatear con %s", FilesystemName(fsType))
 	}
 
 	// 6. Crear e inicializar las estructuras
 
-	// 6.1 Crear el Superbloque
+	// 6.1 Crear el Superbloque; en EXT3 el journal queda entre el superbloque y el bitmap de inodos
+	bmInodeStart, bmBlockStart, inodeStart, blockStart := GetInodesAndBlocksStart(extInfo)
 	superbloque := NewSuperBlock(
 		int32(extInfo.InodeCount),
 		int32(extInfo.BlockCount),
 		int32(extInfo.InodeSize),
 		int32(extInfo.BlockSize),
-		int32(extInfo.SuperBlockSize),
-		int32(extInfo.SuperBlockSize+extInfo.InodeBitmapSize),
-		int32(extInfo.SuperBlockSize+extInfo.InodeBitmapSize+extInfo.BlockBitmapSize),
-		int32(extInfo.SuperBlockSize+extInfo.InodeBitmapSize+extInfo.BlockBitmapSize+extInfo.InodeTableSize),
+		int32(bmInodeStart),
+		int32(bmBlockStart),
+		int32(inodeStart),
+		int32(blockStart),
 	)
+	if fsType == EXT3_FILESYSTEM_TYPE {
+		superbloque.SFilesystemType = EXT3_FILESYSTEM_TYPE
+	}
 
 	// Ajustar contadores de uso
 	superbloque.SFreeBlocksCount = superbloque.SBlocksCount - 2 // Para directorio raíz y users.txt
@@ -92,6 +110,20 @@ func FormatearParticion(id, formatType string) (bool, string) {
 	// 6.3 Reservar los primeros inodos y bloques
 	bitmapMgr.ReserveInitialBlocks(EXT2_RESERVED_INODES, EXT2_RESERVED_INODES)
 
+	// 6.3.1 Marcar los bloques dañados antes de asignar cualquier bloque
+	if len(badBlocks) > MAX_BAD_BLOCKS {
+		return false, fmt.Sprintf("Error: se pueden marcar como máximo %d bloques dañados", MAX_BAD_BLOCKS)
+	}
+	for _, blockNum := range badBlocks {
+		wasFree, err := bitmapMgr.MarkBadBlock(int(blockNum))
+		if err != nil {
+			return false, fmt.Sprintf("Error: bloque dañado inválido: %s", err)
+		}
+		if wasFree {
+			superbloque.SFreeBlocksCount--
+		}
+	}
+
 	// 6.4 Crear el directorio raíz (inodo 2)
 	rootInode := NewInode(0, 0, INODE_FOLDER) // UID 0, GID 0, tipo Carpeta
 	// Establecer permisos razonables
@@ -156,6 +188,14 @@ func FormatearParticion(id, formatType string) (bool, string) {
 	bitmapMgr.MarkInodeAsUsed(usersInodeNum)
 	bitmapMgr.MarkBlockAsUsed(firstUsersBlock)
 
+	// 6.12 Inodo de bloques dañados; si la lista no cabe en los directos usa un bloque de apuntadores
+	badBlocksListBlock := int32(-1)
+	if len(badBlocks) > INDIRECT_BLOCK_INDEX {
+		badBlocksListBlock = int32(bitmapMgr.AllocateBlock())
+		superbloque.SFreeBlocksCount--
+	}
+	badBlocksInode, badBlocksPointers := newBadBlocksInode(badBlocks, badBlocksListBlock, superbloque.SBlockSize)
+
 	// 7. Escribir todas las estructuras en el disco
 
 	// 7.1 Posicionarse al inicio de la partición
@@ -227,7 +267,10 @@ func FormatearParticion(id, formatType string) (bool, string) {
 
 		fmt.Printf("Escribiendo inodo %d en posición %d\n", i, currentPos)
 
-		if i == 2 {
+		if i == EXT2_BAD_BLOCKS_INODE {
+			// Inodo de bloques dañados (#1)
+			err = writeInodeToDisc(file, badBlocksInode)
+		} else if i == 2 {
 			// Inodo raíz (#2)
 			debugInode("Inodo raíz antes de escribir", rootInode)
 			err = writeInodeToDisc(file, rootInode)
@@ -290,6 +333,24 @@ func FormatearParticion(id, formatType string) (bool, string) {
 		return false, fmt.Sprintf("Error al escribir el archivo users.txt: %s", err)
 	}
 
+	// Por último la continuación de la lista de bloques dañados, si la hay
+	if badBlocksPointers != nil {
+		listPos := startByte + int64(superbloque.SBlockStart) + int64(badBlocksListBlock)*int64(superbloque.SBlockSize)
+		if _, err = file.Seek(listPos, 0); err != nil {
+			return false, fmt.Sprintf("Error al posicionarse para escribir la lista de bloques dañados: %s", err)
+		}
+		if err = writePointerBlockToDisc(file, badBlocksPointers); err != nil {
+			return false, fmt.Sprintf("Error al escribir la lista de bloques dañados: %s", err)
+		}
+	}
+
+	// En EXT3 los bloques dañados iniciales también van al journal, para poder recuperarlos
+	if fsType == EXT3_FILESYSTEM_TYPE && len(badBlocks) > 0 {
+		if err = recordInitialBadBlocks(file, startByte, superbloque, badBlocks); err != nil {
+			return false, fmt.Sprintf("Error al registrar los bloques dañados en el journal: %s", err)
+		}
+	}
+
 	// 8. Actualizar el estado de la partición en la estructura interna
 	for _, mp := range utils.MountedPartitions {
 		if mp.ID == id {
@@ -300,13 +361,20 @@ func FormatearParticion(id, formatType string) (bool, string) {
 	// Mostrar el log automáticamente después del formateo exitoso
 	fmt.Println("\n======= INFORMACIÓN DEL FORMATEO EXT2 =======")
 	LogEXT2(id) // Llamar a la función LogEXT2 que ya tenemos
-	fmt.Println("=============================================\n")
+	fmt.Print("=============================================\n\n")
 
-	return true, fmt.Sprintf("Partición %s formateada exitosamente con sistema EXT2", id)
+	mensaje := fmt.Sprintf("Partición %s formateada exitosamente con sistema %s", id, FilesystemName(fsType))
+	if _, encrypted := file.(*EncryptedPartition); encrypted {
+		mensaje += " cifrado"
+	}
+	if len(badBlocks) > 0 {
+		mensaje += fmt.Sprintf(" (%d bloques dañados)", len(badBlocks))
+	}
+	return true, mensaje
 }
 
 // readInodeFromDisc lee un Inode desde disco, manejando time.Time correctamente
-func readInodeFromDisc(file *os.File) (*Inode, error) {
+func readInodeFromDisc(file PartitionFile) (*Inode, error) {
 	inode := &Inode{}
 
 	pos, _ := file.Seek(0, os.SEEK_CUR)
@@ -437,7 +505,9 @@ func GetPartitionDetails(file *os.File, mp *utils.MountedPartition) (int64, int6
 
 			ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
 			if ebrName == mp.PartitionName && ebr.Size > 0 {
-				return currentPos + int64(binary.Size(ebr)), ebr.Size, nil
+				// El tamaño de la lógica incluye su EBR, los datos empiezan después
+				ebrSize := int64(binary.Size(ebr))
+				return currentPos + ebrSize, ebr.Size - ebrSize, nil
 			}
 
 			// Avanzar al siguiente EBR
@@ -452,7 +522,7 @@ func GetPartitionDetails(file *os.File, mp *utils.MountedPartition) (int64, int6
 }
 
 // Implementar writeDirectoryBlockToDisc para asegurar correcta serialización
-func writeDirectoryBlockToDisc(file *os.File, dirBlock *DirectoryBlock) error {
+func writeDirectoryBlockToDisc(file PartitionFile, dirBlock *DirectoryBlock) error {
 	// Crear un buffer con tamaño exacto
 	buf := new(bytes.Buffer)
 
@@ -474,7 +544,7 @@ func writeDirectoryBlockToDisc(file *os.File, dirBlock *DirectoryBlock) error {
 	return err
 }
 
-func writeStructToDisc(file *os.File, data interface{}, blockSize ...int32) error {
+func writeStructToDisc(file PartitionFile, data interface{}, blockSize ...int32) error {
 	switch v := data.(type) {
 	case *SuperBlock:
 		return writeSuperBlockToDisc(file, v)
@@ -501,7 +571,7 @@ func writeStructToDisc(file *os.File, data interface{}, blockSize ...int32) erro
 }
 
 // writeSuperBlockToDisc escribe un SuperBlock en el disco, manejando time.Time correctamente
-func writeSuperBlockToDisc(file *os.File, sb *SuperBlock) error {
+func writeSuperBlockToDisc(file PartitionFile, sb *SuperBlock) error {
 	// Crear un buffer para contener el superbloque serializado
 	buf := new(bytes.Buffer)
 
@@ -564,6 +634,9 @@ func writeSuperBlockToDisc(file *os.File, sb *SuperBlock) error {
 	if err := binary.Write(buf, binary.LittleEndian, sb.SBlockStart); err != nil {
 		return err
 	}
+	if err := binary.Write(buf, binary.LittleEndian, sb.SLastCheck.Unix()); err != nil {
+		return err
+	}
 
 	// Escribir el padding
 	if err := binary.Write(buf, binary.LittleEndian, sb.SPadding); err != nil {
@@ -576,7 +649,7 @@ func writeSuperBlockToDisc(file *os.File, sb *SuperBlock) error {
 }
 
 // Función similar para leer un SuperBlock desde disco
-func ReadSuperBlockFromDisc(file *os.File) (*SuperBlock, error) {
+func ReadSuperBlockFromDisc(file PartitionFile) (*SuperBlock, error) {
 	sb := &SuperBlock{}
 
 	// Leer campos simples primero
@@ -640,6 +713,11 @@ func ReadSuperBlockFromDisc(file *os.File) (*SuperBlock, error) {
 	if err := binary.Read(file, binary.LittleEndian, &sb.SBlockStart); err != nil {
 		return nil, err
 	}
+	var lastCheckUnix int64
+	if err := binary.Read(file, binary.LittleEndian, &lastCheckUnix); err != nil {
+		return nil, err
+	}
+	sb.SLastCheck = time.Unix(lastCheckUnix, 0)
 
 	// Leer el padding
 	if err := binary.Read(file, binary.LittleEndian, &sb.SPadding); err != nil {
@@ -670,7 +748,7 @@ func calculateInodeSize() int {
 }
 
 // 2. Función para escribir un inodo byte a byte
-func writeInodeToDisc(file *os.File, inode *Inode) error {
+func writeInodeToDisc(file PartitionFile, inode *Inode) error {
 	// Imprimir el inodo para depuración
 	debugInode("Escribiendo inodo", inode)
 
@@ -5,29 +5,91 @@ import (
 	"encoding/binary"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
-const CARNET = "202100215"
+const (
+	// MAX_MOUNT_COUNT_ENV cambia cuántos montajes se permiten antes de recomendar una revisión
+	MAX_MOUNT_COUNT_ENV = "MIA_MAX_MOUNT_COUNT"
+	// MAX_CHECK_INTERVAL_ENV cambia el tiempo máximo entre revisiones, en días o como
+	// duración de Go (por ejemplo 720h)
+	MAX_CHECK_INTERVAL_ENV     = "MIA_MAX_CHECK_INTERVAL"
+	DEFAULT_MAX_MOUNT_COUNT    = 20
+	DEFAULT_MAX_CHECK_INTERVAL = 180 * 24 * time.Hour
+)
+
+// GetMaxMountCount retorna el máximo de montajes configurado. Un valor <= 0 lo desactiva.
+func GetMaxMountCount() int32 {
+	value := strings.TrimSpace(os.Getenv(MAX_MOUNT_COUNT_ENV))
+	if value == "" {
+		return DEFAULT_MAX_MOUNT_COUNT
+	}
+	count, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		fmt.Printf("Advertencia: %s='%s' no es un número válido, se usa %d\n",
+			MAX_MOUNT_COUNT_ENV, value, DEFAULT_MAX_MOUNT_COUNT)
+		return DEFAULT_MAX_MOUNT_COUNT
+	}
+	return int32(count)
+}
+
+// GetMaxCheckInterval retorna el intervalo máximo entre revisiones. Un valor <= 0 lo desactiva.
+func GetMaxCheckInterval() time.Duration {
+	value := strings.TrimSpace(os.Getenv(MAX_CHECK_INTERVAL_ENV))
+	if value == "" {
+		return DEFAULT_MAX_CHECK_INTERVAL
+	}
+	if days, err := strconv.Atoi(value); err == nil {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Printf("Advertencia: %s='%s' no es un intervalo válido, se usa %s\n",
+			MAX_CHECK_INTERVAL_ENV, value, DEFAULT_MAX_CHECK_INTERVAL)
+		return DEFAULT_MAX_CHECK_INTERVAL
+	}
+	return interval
+}
 
-// MountPartition monta una partición y devuelve su ID
-func MountPartition(diskPath, partitionName string) (string, error) {
+// MountPartition monta una partición y devuelve su ID. Con readOnly la partición
+// queda en modo solo lectura y las operaciones que escriben en ella se rechazan.
+// Si la partición está formateada también retorna una advertencia cuando conviene
+// revisar el sistema de archivos (vacía si no hace falta).
+func MountPartition(diskPath, partitionName string, readOnly bool) (string, string, error) {
 	// Limpiar nombre de la partición de comillas y espacios
 	partitionName = strings.Trim(partitionName, "\"")
 
 	fmt.Printf("Debug: Buscando partición '%s' en disco '%s'\n", partitionName, diskPath)
 
+	// Una partición cifrada se desbloquea antes con la contraseña
+	if err := requireUnlocked(diskPath, partitionName); err != nil {
+		return "", "", err
+	}
+
+	// Una partición que ya es espejo de otra montada, o parte de un volumen, no se
+	// puede montar por separado
+	for _, mp := range utils.MountedPartitions {
+		if mp.MirrorPath == diskPath && mp.PartitionName == partitionName {
+			return "", "", fmt.Errorf("la partición '%s' es el espejo de la partición montada con ID %s", partitionName, mp.ID)
+		}
+		if mp.PartitionType == PARTITION_VOLUME && mp.UsesPartition(diskPath, partitionName) {
+			return "", "", fmt.Errorf("la partición '%s' forma parte del volumen '%s' (ID %s)", partitionName, mp.PartitionName, mp.ID)
+		}
+	}
+
 	// 1. Abrir el disco
 	file, err := os.OpenFile(diskPath, os.O_RDWR, 0666)
 	if err != nil {
-		return "", fmt.Errorf("error abriendo disco: %v", err)
+		return "", "", fmt.Errorf("error abriendo disco: %v", err)
 	}
 	defer file.Close()
 
 	// 2. Leer el MBR
 	mbr := &MBR{}
 	if err := binary.Read(file, binary.LittleEndian, mbr); err != nil {
-		return "", fmt.Errorf("error leyendo MBR: %v", err)
+		return "", "", fmt.Errorf("error leyendo MBR: %v", err)
 	}
 
 	// Mostrar todas las particiones para depuración
@@ -53,7 +115,7 @@ func MountPartition(diskPath, partitionName string) (string, error) {
 				break
 			} else if p.Type == PARTITION_EXTENDED {
 				// Si es extendida, no se puede montar directamente
-				return "", fmt.Errorf("no se puede montar una partición extendida, solo primarias y lógicas")
+				return "", "", fmt.Errorf("no se puede montar una partición extendida, solo primarias y lógicas")
 			}
 		}
 	}
@@ -83,14 +145,14 @@ func MountPartition(diskPath, partitionName string) (string, error) {
 					break
 				}
 
-				ebrName := strings.TrimRight(string(ebr.Name[:]), " ")
+				ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
 				if ebrName == partitionName && ebr.Size > 0 {
 					// Encontró la partición lógica
 
 					// 4. Verificar si ya está montada
 					for _, mp := range utils.MountedPartitions {
 						if mp.DiskPath == diskPath && mp.PartitionName == partitionName {
-							return mp.ID, fmt.Errorf("la partición '%s' ya está montada con ID: %s", partitionName, mp.ID)
+							return mp.ID, "", fmt.Errorf("la partición '%s' ya está montada con ID: %s", partitionName, mp.ID)
 						}
 					}
 
@@ -98,12 +160,10 @@ func MountPartition(diskPath, partitionName string) (string, error) {
 					ebr.Status = PARTITION_MOUNTED
 
 					// 6. Generar ID para la partición lógica
-					letter := utils.GetNextLetter(diskPath)
-					number := utils.GetNextPartitionNumber(diskPath, letter)
-					id := utils.GenerateID(CARNET, number, letter)
+					id, letter, number := utils.GenerateUniqueID(diskPath)
 
 					// 7. Registrar la partición montada
-					utils.MountedPartitions = append(utils.MountedPartitions, utils.MountedPartition{
+					mounted := utils.MountedPartition{
 						ID:            id,
 						DiskPath:      diskPath,
 						PartitionName: partitionName,
@@ -111,19 +171,23 @@ func MountPartition(diskPath, partitionName string) (string, error) {
 						Status:        PARTITION_MOUNTED,
 						Letter:        letter,
 						Number:        number,
-					})
+						ReadOnly:      readOnly,
+					}
+					utils.MountedPartitions = append(utils.MountedPartitions, mounted)
 
 					// 8. Escribir el EBR actualizado
 					if _, err := file.Seek(currentPos, 0); err != nil {
-						return "", fmt.Errorf("error posicionando cursor: %v", err)
+						return "", "", fmt.Errorf("error posicionando cursor: %v", err)
 					}
 
 					if err := binary.Write(file, binary.LittleEndian, ebr); err != nil {
-						return "", fmt.Errorf("error actualizando EBR: %v", err)
+						return "", "", fmt.Errorf("error actualizando EBR: %v", err)
 					}
 
+					warning := updateSuperBlockMountOrWarn(&mounted)
 					fmt.Printf("Partición lógica '%s' montada exitosamente con ID: %s\n", partitionName, id)
-					return id, nil
+					saveStateOrWarn()
+					return id, warning, nil
 				}
 
 				// Avanzar al siguiente EBR
@@ -137,18 +201,18 @@ func MountPartition(diskPath, partitionName string) (string, error) {
 
 	// Si no se encontró la partición
 	if partition == nil {
-		return "", fmt.Errorf("no se encontró la partición '%s' en el disco", partitionName)
+		return "", "", fmt.Errorf("no se encontró la partición '%s' en el disco", partitionName)
 	}
 
 	// 4. Verificar que sea primaria
 	if partition.Type != PARTITION_PRIMARY {
-		return "", fmt.Errorf("solo se pueden montar particiones primarias y lógicas")
+		return "", "", fmt.Errorf("solo se pueden montar particiones primarias y lógicas")
 	}
 
 	// 5. Verificar si ya está montada
 	for _, mp := range utils.MountedPartitions {
 		if mp.DiskPath == diskPath && mp.PartitionName == partitionName {
-			return mp.ID, fmt.Errorf("la partición '%s' ya está montada con ID: %s", partitionName, mp.ID)
+			return mp.ID, "", fmt.Errorf("la partición '%s' ya está montada con ID: %s", partitionName, mp.ID)
 		}
 	}
 
@@ -156,15 +220,13 @@ func MountPartition(diskPath, partitionName string) (string, error) {
 	partition.Status = PARTITION_MOUNTED
 
 	// 7. Generar ID
-	letter := utils.GetNextLetter(diskPath)
-	number := utils.GetNextPartitionNumber(diskPath, letter)
-	id := utils.GenerateID(CARNET, number, letter)
+	id, letter, number := utils.GenerateUniqueID(diskPath)
 
 	// 8. Actualizar correlativo de la partición
 	partition.Correlative = int32(number)
 
 	// 9. Registrar la partición montada
-	utils.MountedPartitions = append(utils.MountedPartitions, utils.MountedPartition{
+	mounted := utils.MountedPartition{
 		ID:            id,
 		DiskPath:      diskPath,
 		PartitionName: partitionName,
@@ -172,25 +234,52 @@ func MountPartition(diskPath, partitionName string) (string, error) {
 		Status:        PARTITION_MOUNTED,
 		Letter:        letter,
 		Number:        number,
-	})
+		ReadOnly:      readOnly,
+	}
+	utils.MountedPartitions = append(utils.MountedPartitions, mounted)
 
 	// 10. Guardar cambios en el MBR
 	if _, err := file.Seek(0, 0); err != nil {
-		return "", fmt.Errorf("error posicionando cursor: %v", err)
+		return "", "", fmt.Errorf("error posicionando cursor: %v", err)
 	}
 
 	if err := binary.Write(file, binary.LittleEndian, mbr); err != nil {
-		return "", fmt.Errorf("error actualizando MBR: %v", err)
+		return "", "", fmt.Errorf("error actualizando MBR: %v", err)
 	}
 
+	warning := updateSuperBlockMountOrWarn(&mounted)
 	fmt.Printf("Partición primaria '%s' montada exitosamente con ID: %s\n", partitionName, id)
-	return id, nil
+	saveStateOrWarn()
+	return id, warning, nil
 }
 
 // GetMountedPartitions retorna la lista de particiones montadas
 func GetMountedPartitions() []utils.MountedPartition {
 	return utils.MountedPartitions
 }
+
+// CheckWritable retorna un error si la partición está montada en modo solo lectura
+func CheckWritable(id string) error {
+	mp, err := FindMountedPartitionById(id)
+	if err != nil {
+		return err
+	}
+	if mp.ReadOnly {
+		return fmt.Errorf("la partición %s está montada en modo solo lectura", id)
+	}
+	return nil
+}
+
+// FindMountedPartitionByName busca una partición montada por la ruta del disco y su nombre
+func FindMountedPartitionByName(diskPath, partitionName string) (*utils.MountedPartition, error) {
+	for i, mp := range utils.MountedPartitions {
+		if mp.DiskPath == diskPath && mp.PartitionName == partitionName {
+			return &utils.MountedPartitions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("la partición '%s' del disco %s no está montada", partitionName, diskPath)
+}
+
 func IsPartitionMounted(id string) (bool, error) {
 	// Obtener la lista de particiones montadas
 	mountedPartitions := GetMountedPartitions()
@@ -239,7 +328,7 @@ func UnmountPartition(id string) error {
 
 		// Buscar la partición por nombre
 		for i, p := range mbr.MbrPartitions {
-			pName := strings.TrimRight(string(p.Name[:]), " ")
+			pName := strings.TrimRight(string(p.Name[:]), " \x00")
 			if pName == mountedPartition.PartitionName {
 				mbr.MbrPartitions[i].Status = PARTITION_NOT_MOUNTED
 				mbr.MbrPartitions[i].Correlative = -1
@@ -284,7 +373,7 @@ func UnmountPartition(id string) error {
 					break
 				}
 
-				ebrName := strings.TrimRight(string(ebr.Name[:]), " ")
+				ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
 				if ebrName == mountedPartition.PartitionName {
 					// Actualizar estado
 					ebr.Status = PARTITION_NOT_MOUNTED
@@ -310,8 +399,85 @@ func UnmountPartition(id string) error {
 		}
 	}
 
-	// Eliminar la partición del array de montadas
+	// Registrar el desmontaje en el superbloque si la partición está formateada
+	// (un montaje de solo lectura no toca el sistema de archivos)
+	if !mountedPartition.ReadOnly {
+		if err := updateSuperBlockUnmount(&mountedPartition); err != nil {
+			fmt.Printf("Advertencia: no se pudo actualizar el superbloque de %s: %v\n", id, err)
+		}
+	}
+
+	// La clave de una partición cifrada no se conserva después de desmontarla
+	ForgetPartitionKey(mountedPartition.DiskPath, mountedPartition.PartitionName)
+
+	// Eliminar la partición del array de montadas (libera el ID y, si era la última del disco, la letra)
 	utils.MountedPartitions = append(utils.MountedPartitions[:foundIndex], utils.MountedPartitions[foundIndex+1:]...)
+	saveStateOrWarn()
+
+	return nil
+}
 
+// updateSuperBlockMountOrWarn registra el montaje en el superbloque EXT2 y retorna la
+// advertencia de revisión. Un montaje de solo lectura no escribe en el sistema de archivos.
+// Los errores no impiden el montaje, solo se informan.
+func updateSuperBlockMountOrWarn(mp *utils.MountedPartition) string {
+	file, startByte, _, err := OpenMountedPartition(mp, os.O_RDWR)
+	if err != nil {
+		fmt.Printf("Advertencia: no se pudo leer el superbloque de %s: %v\n", mp.ID, err)
+		return ""
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(startByte, 0); err != nil {
+		fmt.Printf("Advertencia: no se pudo leer el superbloque de %s: %v\n", mp.ID, err)
+		return ""
+	}
+	sb, err := ReadSuperBlockFromDisc(file)
+	if err != nil || sb.SMagic != EXT2_MAGIC {
+		// La partición no tiene sistema de archivos, no hay nada que actualizar
+		return ""
+	}
+
+	if !mp.ReadOnly {
+		sb.UpdateMountInfo()
+		if _, err := file.Seek(startByte, 0); err != nil {
+			fmt.Printf("Advertencia: no se pudo actualizar el superbloque de %s: %v\n", mp.ID, err)
+		} else if err := writeSuperBlockToDisc(file, sb); err != nil {
+			fmt.Printf("Advertencia: no se pudo actualizar el superbloque de %s: %v\n", mp.ID, err)
+		}
+	}
+
+	warning := sb.CheckRecommendation(GetMaxMountCount(), GetMaxCheckInterval())
+	if warning != "" {
+		fmt.Printf("Advertencia: %s: %s\n", mp.ID, warning)
+	}
+	return warning
+}
+
+// updateSuperBlockUnmount actualiza la fecha de desmontaje del superbloque EXT2
+func updateSuperBlockUnmount(mp *utils.MountedPartition) error {
+	file, startByte, _, err := OpenMountedPartition(mp, os.O_RDWR)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+	}
+	sb, err := ReadSuperBlockFromDisc(file)
+	if err != nil || sb.SMagic != EXT2_MAGIC {
+		// La partición no tiene sistema de archivos, no hay nada que actualizar
+		return nil
+	}
+
+	sb.UpdateUnmountInfo()
+
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+	}
+	if err := writeSuperBlockToDisc(file, sb); err != nil {
+		return fmt.Errorf("error escribiendo superbloque: %v", err)
+	}
 	return nil
 }
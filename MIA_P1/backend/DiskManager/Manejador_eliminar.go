@@ -0,0 +1,241 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Modos de eliminación de particiones
+const (
+	DELETE_FAST = "fast"
+	DELETE_FULL = "full"
+)
+
+// DeletePartition elimina una partición primaria, extendida o lógica por nombre.
+// Con el modo "fast" solo se libera la entrada en el MBR o en la cadena de EBRs;
+// con el modo "full" además se rellena con ceros el espacio que ocupaba.
+func (pm *PartitionManager) DeletePartition(name string, mode string) error {
+	name = strings.Trim(name, "\"")
+	mode = strings.ToLower(mode)
+	if mode != DELETE_FAST && mode != DELETE_FULL {
+		return fmt.Errorf("modo de eliminación inválido '%s', debe ser fast o full", mode)
+	}
+
+	file, err := os.OpenFile(pm.diskPath, os.O_RDWR, 0666)
+	if err != nil {
+		return fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	// Leer MBR actual del disco
+	currentMBR := &MBR{}
+	if err := binary.Read(file, binary.LittleEndian, currentMBR); err != nil {
+		return fmt.Errorf("error leyendo MBR: %v", err)
+	}
+
+	// 1. Buscar en las particiones primarias y extendida
+	for i, p := range currentMBR.MbrPartitions {
+		pName := strings.TrimRight(string(p.Name[:]), " \x00")
+		if p.Size <= 0 || pName != name {
+			continue
+		}
+
+		if id, mounted := pm.mountedID(name); mounted {
+			return fmt.Errorf("la partición '%s' está montada con ID %s, desmóntela antes de eliminarla", name, id)
+		}
+
+		// Si es extendida, las lógicas se eliminan en cascada
+		var logicals []*EBR
+		if p.Type == PARTITION_EXTENDED {
+			logicals, err = pm.getAllEBRs(&p)
+			if err != nil {
+				return fmt.Errorf("error leyendo particiones lógicas: %v", err)
+			}
+			for _, ebr := range logicals {
+				ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
+				if ebr.Size <= 0 {
+					continue
+				}
+				if id, mounted := pm.mountedID(ebrName); mounted {
+					return fmt.Errorf("la partición lógica '%s' está montada con ID %s, desmóntela antes de eliminar la extendida", ebrName, id)
+				}
+			}
+		}
+
+		fmt.Printf("Debug: Eliminando partición '%s' (Type=%c, Start=%d, Size=%d, modo=%s)\n",
+			name, p.Type, p.Start, p.Size, mode)
+
+		if mode == DELETE_FULL {
+			if err := zeroDiskRegion(file, p.Start, p.Size); err != nil {
+				return fmt.Errorf("error limpiando espacio de la partición: %v", err)
+			}
+		} else if p.Type == PARTITION_EXTENDED {
+			// En modo fast se invalidan los EBRs para que las lógicas desaparezcan
+			for _, ebr := range logicals {
+				if ebr.Start < p.Start || ebr.Start >= p.Start+p.Size {
+					continue
+				}
+				if _, err := file.Seek(ebr.Start, 0); err != nil {
+					return fmt.Errorf("error posicionando cursor para EBR: %v", err)
+				}
+				if err := binary.Write(file, binary.LittleEndian, NewEBR()); err != nil {
+					return fmt.Errorf("error limpiando EBR: %v", err)
+				}
+			}
+		}
+
+		// Dejar el slot como lo deja mkdisk
+		currentMBR.MbrPartitions[i] = emptyPartitionSlot()
+
+		if _, err := file.Seek(0, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor para escribir MBR: %v", err)
+		}
+		if err := binary.Write(file, binary.LittleEndian, currentMBR); err != nil {
+			return fmt.Errorf("error escribiendo MBR: %v", err)
+		}
+
+		LogMBR(pm.diskPath)
+		return nil
+	}
+
+	// 2. Buscar en las particiones lógicas
+	var extended *Partition
+	for i, p := range currentMBR.MbrPartitions {
+		if p.Type == PARTITION_EXTENDED && p.Size > 0 {
+			extended = &currentMBR.MbrPartitions[i]
+			break
+		}
+	}
+	if extended == nil {
+		return fmt.Errorf("no se encontró la partición '%s' en el disco", name)
+	}
+
+	if err := pm.deleteLogicalPartition(file, extended, name, mode); err != nil {
+		return err
+	}
+
+	LogMBR(pm.diskPath)
+	return nil
+}
+
+// deleteLogicalPartition desenlaza el EBR de una partición lógica de la cadena
+func (pm *PartitionManager) deleteLogicalPartition(file *os.File, extended *Partition, name string, mode string) error {
+	ebrSize := int64(binary.Size(EBR{}))
+	limit := extended.Start + extended.Size
+
+	var prev *EBR
+	var prevPos int64 = -1
+	currentPos := extended.Start
+	visited := make(map[int64]bool)
+
+	for currentPos != -1 {
+		if currentPos < extended.Start || currentPos+ebrSize > limit || visited[currentPos] {
+			break
+		}
+		visited[currentPos] = true
+
+		if _, err := file.Seek(currentPos, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor: %v", err)
+		}
+		ebr := &EBR{}
+		if err := binary.Read(file, binary.LittleEndian, ebr); err != nil {
+			return fmt.Errorf("error leyendo EBR: %v", err)
+		}
+
+		ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
+		if ebr.Size > 0 && ebrName == name {
+			if id, mounted := pm.mountedID(name); mounted {
+				return fmt.Errorf("la partición '%s' está montada con ID %s, desmóntela antes de eliminarla", name, id)
+			}
+
+			fmt.Printf("Debug: Eliminando partición lógica '%s' (EBR en %d, Size=%d, modo=%s)\n",
+				name, currentPos, ebr.Size, mode)
+
+			// Limpiar el área de datos de la lógica (el EBR se reescribe abajo)
+			if mode == DELETE_FULL && ebr.Size > ebrSize {
+				if err := zeroDiskRegion(file, currentPos+ebrSize, ebr.Size-ebrSize); err != nil {
+					return fmt.Errorf("error limpiando espacio de la partición lógica: %v", err)
+				}
+			}
+
+			if prev == nil {
+				// El primer EBR nunca se mueve: queda vacío pero conserva el enlace
+				head := NewEBR()
+				head.Start = currentPos
+				head.Next = ebr.Next
+				if _, err := file.Seek(currentPos, 0); err != nil {
+					return fmt.Errorf("error posicionando cursor para EBR: %v", err)
+				}
+				if err := binary.Write(file, binary.LittleEndian, head); err != nil {
+					return fmt.Errorf("error actualizando EBR inicial: %v", err)
+				}
+				return nil
+			}
+
+			// Enlazar el EBR anterior con el siguiente
+			prev.Next = ebr.Next
+			if _, err := file.Seek(prevPos, 0); err != nil {
+				return fmt.Errorf("error posicionando cursor para EBR: %v", err)
+			}
+			if err := binary.Write(file, binary.LittleEndian, prev); err != nil {
+				return fmt.Errorf("error actualizando EBR anterior: %v", err)
+			}
+
+			if mode == DELETE_FULL {
+				if err := zeroDiskRegion(file, currentPos, ebrSize); err != nil {
+					return fmt.Errorf("error limpiando EBR: %v", err)
+				}
+			}
+			return nil
+		}
+
+		prev = ebr
+		prevPos = currentPos
+		currentPos = ebr.Next
+	}
+
+	return fmt.Errorf("no se encontró la partición '%s' en el disco", name)
+}
+
+// mountedID indica si una partición de este disco está en la lista de montadas,
+// ya sea montada directamente o como espejo de otra
+func (pm *PartitionManager) mountedID(name string) (string, bool) {
+	for _, mp := range utils.MountedPartitions {
+		if mp.UsesPartition(pm.diskPath, name) {
+			return mp.ID, true
+		}
+	}
+	return "", false
+}
+
+// emptyPartitionSlot retorna un slot del MBR con los valores iniciales de mkdisk
+func emptyPartitionSlot() Partition {
+	return Partition{
+		Status: '0',
+		Type:   '0',
+		Fit:    'F',
+	}
+}
+
+// zeroDiskRegion escribe ceros en una región del disco por bloques
+func zeroDiskRegion(file *os.File, start int64, size int64) error {
+	if _, err := file.Seek(start, 0); err != nil {
+		return err
+	}
+
+	zeroBuffer := make([]byte, ZERO_FILL_CHUNK)
+	for size > 0 {
+		writeSize := int64(ZERO_FILL_CHUNK)
+		if size < writeSize {
+			writeSize = size
+		}
+		if _, err := file.Write(zeroBuffer[:writeSize]); err != nil {
+			return err
+		}
+		size -= writeSize
+	}
+	return nil
+}
@@ -377,7 +377,7 @@ func (pm *PartitionManager) validateUniqueName(name []byte) error {
 		if err == nil {
 			for _, ebr := range ebrs {
 				if ebr.Size > 0 {
-					ebrName := strings.TrimRight(string(ebr.Name[:]), " ")
+					ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
 					if ebrName == newName {
 						return fmt.Errorf("ya existe una partición lógica con el nombre '%s'", newName)
 					}
@@ -417,7 +417,54 @@ func (pm *PartitionManager) getAllEBRs(extendedPartition *Partition) ([]*EBR, er
 	return ebrs, nil
 }
 
-// findSpaceForLogicalPartition busca espacio para una nueva partición lógica
+// logicalChainEntry es un EBR de la cadena junto con la posición donde está escrito
+type logicalChainEntry struct {
+	pos int64
+	ebr *EBR
+}
+
+// readLogicalChain recorre la cadena de EBRs de la extendida. El primer EBR solo se incluye
+// si tiene una lógica o si enlaza con otros EBRs (queda vacío al eliminar su lógica).
+func (pm *PartitionManager) readLogicalChain(file *os.File, extendedPartition *Partition) []logicalChainEntry {
+	var chain []logicalChainEntry
+	ebrSize := int64(binary.Size(EBR{}))
+	limit := extendedPartition.Start + extendedPartition.Size
+	visited := make(map[int64]bool)
+
+	currentPos := extendedPartition.Start
+	for currentPos != -1 {
+		if currentPos < extendedPartition.Start || currentPos+ebrSize > limit || visited[currentPos] {
+			break
+		}
+		visited[currentPos] = true
+
+		if _, err := file.Seek(currentPos, 0); err != nil {
+			break
+		}
+		ebr := &EBR{}
+		if err := binary.Read(file, binary.LittleEndian, ebr); err != nil {
+			break
+		}
+
+		hasNext := ebr.Next > currentPos && ebr.Next+ebrSize <= limit
+		if ebr.Size <= 0 && !hasNext {
+			// EBR vacío al final de la cadena (o extendida recién creada)
+			break
+		}
+
+		chain = append(chain, logicalChainEntry{pos: currentPos, ebr: ebr})
+		if !hasNext {
+			break
+		}
+		currentPos = ebr.Next
+	}
+
+	return chain
+}
+
+// findSpaceForLogicalPartition busca espacio para una nueva partición lógica dentro de la
+// extendida aplicando el ajuste de la partición (FF, BF o WF) sobre los huecos libres,
+// incluidos los que dejan las lógicas eliminadas, y la enlaza en la cadena de EBRs.
 func (pm *PartitionManager) findSpaceForLogicalPartition(extendedPartition *Partition, ebr *EBR) error {
 	// Abrir el archivo de disco
 	file, err := os.OpenFile(pm.diskPath, os.O_RDWR, 0666)
@@ -426,122 +473,80 @@ func (pm *PartitionManager) findSpaceForLogicalPartition(extendedPartition *Part
 	}
 	defer file.Close()
 
-	// Verificar que la partición extendida tiene suficiente espacio para el EBR inicial
 	ebrSize := int64(binary.Size(ebr))
 
-	// Comprobar si hay suficiente espacio para la partición lógica
-	if ebrSize+ebr.Size > extendedPartition.Size {
+	// Comprobar si hay suficiente espacio para la partición lógica (el tamaño incluye su EBR)
+	if ebr.Size < ebrSize || ebr.Size > extendedPartition.Size {
 		return fmt.Errorf("la partición lógica es demasiado grande para la partición extendida")
 	}
 
 	fmt.Printf("Debug: Partición extendida: Start=%d, Size=%d\n",
 		extendedPartition.Start, extendedPartition.Size)
-	fmt.Printf("Debug: Tamaño de EBR: %d, Tamaño total requerido: %d\n",
-		ebrSize, ebrSize+ebr.Size)
 
-	// Posicionarse al inicio de la partición extendida
-	if _, err := file.Seek(extendedPartition.Start, 0); err != nil {
-		return fmt.Errorf("error posicionando cursor: %v", err)
+	// 1. Espacios ocupados por las lógicas existentes
+	chain := pm.readLogicalChain(file, extendedPartition)
+	var reserved []Space
+	for _, entry := range chain {
+		if entry.ebr.Size > 0 {
+			reserved = append(reserved, Space{start: entry.pos, size: entry.ebr.Size})
+			fmt.Printf("Debug: Lógica '%s' ocupa start=%d, size=%d\n",
+				strings.TrimRight(string(entry.ebr.Name[:]), " \x00"), entry.pos, entry.ebr.Size)
+		}
 	}
 
-	// Leer el primer EBR
-	firstEBR := &EBR{}
-	err = binary.Read(file, binary.LittleEndian, firstEBR)
-
-	// Si es la primera partición lógica o no hay un EBR válido, crear uno al inicio
-	if err != nil || firstEBR.Size == 0 {
-		fmt.Printf("Debug: Creando primera partición lógica en la partición extendida\n")
-		ebr.Start = extendedPartition.Start
-		ebr.Next = -1
-		return nil
+	// 2. Huecos libres dentro de la extendida y selección según el ajuste
+	spaces := freeSpacesBetween(extendedPartition.Start, extendedPartition.Start+extendedPartition.Size, reserved)
+	for _, space := range spaces {
+		fmt.Printf("Debug: Hueco libre en extendida: start=%d, size=%d\n", space.start, space.size)
 	}
 
-	fmt.Printf("Debug: Buscando espacio en la cadena de EBRs existentes\n")
-
-	// Navegar por la lista enlazada para encontrar el último EBR válido
-	currentEBR := firstEBR
-	var lastValidEBR *EBR = nil
-	var lastPosition int64 = extendedPartition.Start
-
-	for {
-		// Si este EBR es válido, actualizamos lastValidEBR
-		if currentEBR.Size > 0 {
-			lastValidEBR = currentEBR
-			lastPosition = currentEBR.Start
-
-			fmt.Printf("Debug: EBR válido encontrado en %d, Size=%d, Next=%d\n",
-				currentEBR.Start, currentEBR.Size, currentEBR.Next)
-		}
-
-		// Si no hay siguiente EBR, salimos del bucle
-		if currentEBR.Next == -1 {
-			break
-		}
-
-		// Leemos el siguiente EBR
-		nextPos := currentEBR.Next
-		if nextPos < extendedPartition.Start ||
-			nextPos >= extendedPartition.Start+extendedPartition.Size {
-			// El puntero Next está fuera de la partición extendida
-			fmt.Printf("Debug: Error: puntero Next (%d) fuera de la partición extendida\n", nextPos)
-			break
-		}
-
-		if _, err := file.Seek(nextPos, 0); err != nil {
-			fmt.Printf("Debug: Error posicionando cursor: %v\n", err)
-			break
-		}
+	selected := pm.fit.selectSpace(spaces, ebr.Size, ebr.Fit)
+	if selected == nil {
+		return fmt.Errorf("no hay espacio suficiente en la partición extendida")
+	}
+	newStart := selected.start
 
-		nextEBR := &EBR{}
-		if err := binary.Read(file, binary.LittleEndian, nextEBR); err != nil {
-			fmt.Printf("Debug: Error leyendo siguiente EBR: %v\n", err)
-			break
-		}
+	fmt.Printf("Debug: Ajuste %c seleccionó la posición %d\n", ebr.Fit, newStart)
 
-		currentEBR = nextEBR
-	}
+	// 3. Enlazar el nuevo EBR en la cadena, ordenada por posición
+	ebr.Start = newStart
+	ebr.Next = -1
 
-	// Calcular la nueva posición después del último EBR válido
-	var newStart int64
-	if lastValidEBR != nil {
-		newStart = lastValidEBR.Start + lastValidEBR.Size
-		fmt.Printf("Debug: Última posición válida: %d, nueva posición: %d\n",
-			lastPosition, newStart)
-	} else {
-		// Si no encontramos un EBR válido, usamos el inicio de la partición extendida
-		newStart = extendedPartition.Start
-		fmt.Printf("Debug: No se encontraron EBRs válidos, usando inicio de la extendida: %d\n",
-			newStart)
+	if len(chain) == 0 {
+		fmt.Printf("Debug: Creando primera partición lógica en la partición extendida\n")
+		return nil
 	}
 
-	// Verificar si hay espacio suficiente en la partición extendida
-	if newStart+ebrSize+ebr.Size > extendedPartition.Start+extendedPartition.Size {
-		return fmt.Errorf("no hay espacio suficiente en la partición extendida")
+	if newStart == extendedPartition.Start {
+		// El primer EBR está vacío: se reutiliza conservando su enlace
+		fmt.Printf("Debug: Reutilizando el primer EBR vacío de la partición extendida\n")
+		ebr.Next = chain[0].ebr.Next
+		return nil
 	}
 
-	// Actualizar el último EBR para que apunte al nuevo
-	if lastValidEBR != nil {
-		lastValidEBR.Next = newStart
-
-		// Escribir el último EBR actualizado
-		if _, err := file.Seek(lastValidEBR.Start, 0); err != nil {
-			return fmt.Errorf("error posicionando cursor: %v", err)
+	var prev *logicalChainEntry
+	for i := range chain {
+		if chain[i].pos < newStart && (prev == nil || chain[i].pos > prev.pos) {
+			prev = &chain[i]
 		}
+	}
+	if prev == nil {
+		return fmt.Errorf("no se encontró el EBR anterior a la posición %d", newStart)
+	}
 
-		if err := binary.Write(file, binary.LittleEndian, lastValidEBR); err != nil {
-			return fmt.Errorf("error actualizando último EBR: %v", err)
-		}
+	ebr.Next = prev.ebr.Next
+	prev.ebr.Next = newStart
 
-		fmt.Printf("Debug: Actualizado EBR en posición %d para apuntar a %d\n",
-			lastValidEBR.Start, newStart)
+	if _, err := file.Seek(prev.pos, 0); err != nil {
+		return fmt.Errorf("error posicionando cursor: %v", err)
+	}
+	if err := binary.Write(file, binary.LittleEndian, prev.ebr); err != nil {
+		return fmt.Errorf("error actualizando EBR anterior: %v", err)
 	}
 
-	// Asignar posición al nuevo EBR
-	ebr.Start = newStart
-	ebr.Next = -1
-
-	fmt.Printf("Debug: Nuevo EBR será creado en Start=%d con Size=%d\n",
-		ebr.Start, ebr.Size)
+	fmt.Printf("Debug: Actualizado EBR en posición %d para apuntar a %d\n", prev.pos, newStart)
+	fmt.Printf("Debug: Nuevo EBR será creado en Start=%d con Size=%d, Next=%d\n",
+		ebr.Start, ebr.Size, ebr.Next)
 
 	return nil
 }
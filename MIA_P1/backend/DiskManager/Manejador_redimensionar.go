@@ -0,0 +1,272 @@
+package DiskManager
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ResizePartition agrega (add > 0) o quita (add < 0) espacio a una partición existente
+// sin moverla de lugar. Al crecer solo se usa el espacio libre contiguo al final de la
+// partición; al reducir no se permite cortar metadatos ni bloques en uso de EXT2.
+func (pm *PartitionManager) ResizePartition(name string, add int64, unit string) error {
+	name = strings.Trim(name, "\"")
+	delta := pm.convertToBytes(add, unit)
+	if delta == 0 {
+		return fmt.Errorf("el valor de add no puede ser cero")
+	}
+
+	// Una partición montada, o que es espejo o parte de un volumen montado, tiene su
+	// sistema de archivos en uso
+	if id, mounted := pm.mountedID(name); mounted {
+		return fmt.Errorf("la partición '%s' está montada con ID %s, desmóntela antes de redimensionarla", name, id)
+	}
+
+	// En una partición cifrada el superbloque no se puede revisar sin la clave, y el
+	// espacio agregado no tendría sectores cifrados válidos
+	if encrypted, _ := IsPartitionEncrypted(pm.diskPath, name); encrypted {
+		return fmt.Errorf("la partición '%s' está cifrada, no se puede redimensionar", name)
+	}
+
+	file, err := os.OpenFile(pm.diskPath, os.O_RDWR, 0666)
+	if err != nil {
+		return fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	// Leer MBR actual del disco
+	currentMBR := &MBR{}
+	if err := binary.Read(file, binary.LittleEndian, currentMBR); err != nil {
+		return fmt.Errorf("error leyendo MBR: %v", err)
+	}
+
+	// 1. Buscar en las particiones primarias y extendida
+	for i, p := range currentMBR.MbrPartitions {
+		pName := strings.TrimRight(string(p.Name[:]), " \x00")
+		if p.Size <= 0 || pName != name {
+			continue
+		}
+
+		newSize := p.Size + delta
+		if newSize <= 0 {
+			return fmt.Errorf("no se puede quitar %d bytes, la partición '%s' solo tiene %d bytes", -delta, name, p.Size)
+		}
+
+		var updatedSB *SuperBlock
+		if delta > 0 {
+			// Buscar el hueco libre que comienza justo donde termina la partición
+			available := int64(0)
+			for _, space := range pm.fit.getFreeSpaces(currentMBR) {
+				if space.start == p.Start+p.Size {
+					available = space.size
+					break
+				}
+			}
+			if available < delta {
+				return fmt.Errorf("no hay espacio libre contiguo suficiente después de '%s': disponible %d bytes, requerido %d bytes",
+					name, available, delta)
+			}
+		} else if p.Type == PARTITION_EXTENDED {
+			// Las lógicas deben seguir cabiendo dentro de la extendida
+			ebrs, err := pm.getAllEBRs(&p)
+			if err != nil {
+				return fmt.Errorf("error leyendo particiones lógicas: %v", err)
+			}
+			minEnd := p.Start + int64(binary.Size(EBR{}))
+			for _, ebr := range ebrs {
+				if ebr.Size > 0 && ebr.Start+ebr.Size > minEnd {
+					minEnd = ebr.Start + ebr.Size
+				}
+			}
+			if p.Start+newSize < minEnd {
+				return fmt.Errorf("no se puede reducir la extendida '%s' a %d bytes, sus particiones lógicas ocupan hasta %d bytes",
+					name, newSize, minEnd-p.Start)
+			}
+		} else {
+			updatedSB, err = shrinkEXT2(file, p.Start, newSize)
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("Debug: Redimensionando partición '%s': Size=%d -> %d\n", name, p.Size, newSize)
+		currentMBR.MbrPartitions[i].Size = newSize
+
+		if _, err := file.Seek(0, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor para escribir MBR: %v", err)
+		}
+		if err := binary.Write(file, binary.LittleEndian, currentMBR); err != nil {
+			return fmt.Errorf("error escribiendo MBR: %v", err)
+		}
+
+		if updatedSB != nil {
+			if _, err := file.Seek(p.Start, 0); err != nil {
+				return fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+			}
+			if err := writeSuperBlockToDisc(file, updatedSB); err != nil {
+				return fmt.Errorf("error actualizando superbloque: %v", err)
+			}
+		}
+
+		LogMBR(pm.diskPath)
+		return nil
+	}
+
+	// 2. Buscar en las particiones lógicas
+	var extended *Partition
+	for i, p := range currentMBR.MbrPartitions {
+		if p.Type == PARTITION_EXTENDED && p.Size > 0 {
+			extended = &currentMBR.MbrPartitions[i]
+			break
+		}
+	}
+	if extended == nil {
+		return fmt.Errorf("no se encontró la partición '%s' en el disco", name)
+	}
+
+	if err := pm.resizeLogicalPartition(file, extended, name, delta); err != nil {
+		return err
+	}
+
+	LogMBR(pm.diskPath)
+	return nil
+}
+
+// resizeLogicalPartition cambia el tamaño de una lógica respetando el siguiente EBR de la cadena
+func (pm *PartitionManager) resizeLogicalPartition(file *os.File, extended *Partition, name string, delta int64) error {
+	ebrSize := int64(binary.Size(EBR{}))
+	limit := extended.Start + extended.Size
+	currentPos := extended.Start
+	visited := make(map[int64]bool)
+
+	for currentPos != -1 {
+		if currentPos < extended.Start || currentPos+ebrSize > limit || visited[currentPos] {
+			break
+		}
+		visited[currentPos] = true
+
+		if _, err := file.Seek(currentPos, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor: %v", err)
+		}
+		ebr := &EBR{}
+		if err := binary.Read(file, binary.LittleEndian, ebr); err != nil {
+			return fmt.Errorf("error leyendo EBR: %v", err)
+		}
+
+		ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
+		if ebr.Size <= 0 || ebrName != name {
+			currentPos = ebr.Next
+			continue
+		}
+
+		newSize := ebr.Size + delta
+		if newSize <= ebrSize {
+			return fmt.Errorf("no se puede quitar %d bytes, la partición '%s' solo tiene %d bytes", -delta, name, ebr.Size)
+		}
+
+		var updatedSB *SuperBlock
+		if delta > 0 {
+			// El límite es el siguiente EBR o el final de la extendida
+			boundary := limit
+			if ebr.Next != -1 {
+				boundary = ebr.Next
+			}
+			available := boundary - (currentPos + ebr.Size)
+			if available < delta {
+				return fmt.Errorf("no hay espacio libre contiguo suficiente después de '%s': disponible %d bytes, requerido %d bytes",
+					name, available, delta)
+			}
+		} else {
+			var err error
+			updatedSB, err = shrinkEXT2(file, currentPos+ebrSize, newSize-ebrSize)
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("Debug: Redimensionando partición lógica '%s': Size=%d -> %d\n", name, ebr.Size, newSize)
+		ebr.Size = newSize
+
+		if _, err := file.Seek(currentPos, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor para EBR: %v", err)
+		}
+		if err := binary.Write(file, binary.LittleEndian, ebr); err != nil {
+			return fmt.Errorf("error actualizando EBR: %v", err)
+		}
+
+		if updatedSB != nil {
+			if _, err := file.Seek(currentPos+ebrSize, 0); err != nil {
+				return fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+			}
+			if err := writeSuperBlockToDisc(file, updatedSB); err != nil {
+				return fmt.Errorf("error actualizando superbloque: %v", err)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no se encontró la partición '%s' en el disco", name)
+}
+
+// shrinkEXT2 verifica que una partición formateada pueda reducirse a newSize bytes.
+// Si el sistema de archivos se ve afectado retorna el superbloque con el nuevo conteo
+// de bloques; si la partición no está formateada o no se recortan bloques retorna nil.
+func shrinkEXT2(file *os.File, startByte int64, newSize int64) (*SuperBlock, error) {
+	if _, err := file.Seek(startByte, 0); err != nil {
+		return nil, fmt.Errorf("error posicionando cursor para superbloque: %v", err)
+	}
+	sb, err := ReadSuperBlockFromDisc(file)
+	if err != nil || sb.SMagic != EXT2_MAGIC || sb.SBlockSize <= 0 {
+		// No tiene sistema de archivos, se puede reducir libremente
+		return nil, nil
+	}
+
+	blockSize := int64(sb.SBlockSize)
+	fsEnd := int64(sb.SBlockStart) + int64(sb.SBlocksCount)*blockSize
+	if newSize >= fsEnd {
+		return nil, nil
+	}
+
+	if newSize < int64(sb.SBlockStart) {
+		return nil, fmt.Errorf("no se puede reducir a %d bytes: se cortarían los metadatos EXT2 (superbloque, bitmaps y tabla de inodos ocupan %d bytes)",
+			newSize, sb.SBlockStart)
+	}
+
+	blockBitmap, err := loadBlockBitmap(file, startByte, sb)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buscar el último bloque en uso
+	lastUsed := int32(-1)
+	for i := sb.SBlocksCount - 1; i >= 0; i-- {
+		if blockBitmap[i/8]&(1<<(i%8)) != 0 {
+			lastUsed = i
+			break
+		}
+	}
+
+	required := int64(sb.SBlockStart) + int64(lastUsed+1)*blockSize
+	if newSize < required {
+		return nil, fmt.Errorf("no se puede reducir a %d bytes: el bloque %d está en uso y requiere al menos %d bytes",
+			newSize, lastUsed, required)
+	}
+
+	// Ajustar el superbloque a los bloques que siguen cabiendo
+	newCount := int32((newSize - int64(sb.SBlockStart)) / blockSize)
+	removed := sb.SBlocksCount - newCount
+	sb.SBlocksCount = newCount
+	sb.SFreeBlocksCount -= removed
+	if sb.SFreeBlocksCount < 0 {
+		sb.SFreeBlocksCount = 0
+	}
+	if sb.SFirstBlo >= newCount {
+		sb.SFirstBlo = lastUsed + 1
+	}
+
+	fmt.Printf("Debug: Superbloque ajustado: SBlocksCount=%d, SFreeBlocksCount=%d\n",
+		sb.SBlocksCount, sb.SFreeBlocksCount)
+
+	return sb, nil
+}
@@ -0,0 +1,620 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Estados del espejo de una partición montada con -mirror
+const (
+	MIRROR_SYNCED   = "sincronizado"   // ambas copias son iguales y se escriben juntas
+	MIRROR_STALE    = "desactualizado" // el espejo no tiene los últimos cambios, necesita resync
+	MIRROR_DEGRADED = "degradado"      // no se pudo escribir o abrir el espejo
+)
+
+// mirrorPair agrupa los dos discos de un volumen espejo y la zona de datos de la partición en cada uno
+type mirrorPair struct {
+	primary, mirror             *os.File
+	primaryRegion, mirrorRegion *Partition
+}
+
+func (p *mirrorPair) Close() {
+	p.primary.Close()
+	p.mirror.Close()
+}
+
+// openMirrorPair abre ambos discos con flag y ubica la partición name en cada uno. Las
+// dos copias deben tener el mismo tamaño de datos para poder espejarse.
+func openMirrorPair(diskPath, mirrorPath, name string, flag int) (*mirrorPair, error) {
+	primaryManager, err := NewPartitionManager(diskPath)
+	if err != nil {
+		return nil, err
+	}
+	mirrorManager, err := NewPartitionManager(mirrorPath)
+	if err != nil {
+		return nil, fmt.Errorf("disco espejo: %v", err)
+	}
+
+	primary, err := os.OpenFile(diskPath, flag, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	mirror, err := os.OpenFile(mirrorPath, flag, 0666)
+	if err != nil {
+		primary.Close()
+		return nil, fmt.Errorf("error abriendo disco espejo: %v", err)
+	}
+	pair := &mirrorPair{primary: primary, mirror: mirror}
+
+	if pair.primaryRegion, err = primaryManager.findDataRegion(primary, name); err != nil {
+		pair.Close()
+		return nil, err
+	}
+	if pair.mirrorRegion, err = mirrorManager.findDataRegion(mirror, name); err != nil {
+		pair.Close()
+		return nil, fmt.Errorf("disco espejo: %v", err)
+	}
+	if pair.primaryRegion.Size != pair.mirrorRegion.Size {
+		pair.Close()
+		return nil, fmt.Errorf("la partición '%s' no tiene el mismo tamaño en ambos discos (%d y %d bytes)",
+			name, pair.primaryRegion.Size, pair.mirrorRegion.Size)
+	}
+	return pair, nil
+}
+
+// syncRegion compara size bytes de src y dest por bloques y, si apply es verdadero, copia
+// los bloques que difieren. Retorna cuántos bytes difieren. A diferencia de copyDiskRegion
+// también escribe los bloques en cero, porque el destino puede tener datos viejos.
+func syncRegion(src *os.File, srcStart int64, dest *os.File, destStart int64, size int64, apply bool) (int64, error) {
+	srcBuffer := make([]byte, CLONE_CHUNK)
+	destBuffer := make([]byte, CLONE_CHUNK)
+	var diff int64
+
+	for offset := int64(0); offset < size; offset += CLONE_CHUNK {
+		chunk := int64(CLONE_CHUNK)
+		if size-offset < chunk {
+			chunk = size - offset
+		}
+		if _, err := src.ReadAt(srcBuffer[:chunk], srcStart+offset); err != nil {
+			return diff, err
+		}
+		if _, err := dest.ReadAt(destBuffer[:chunk], destStart+offset); err != nil {
+			return diff, err
+		}
+		if bytes.Equal(srcBuffer[:chunk], destBuffer[:chunk]) {
+			continue
+		}
+		diff += chunk
+		if !apply {
+			continue
+		}
+		if _, err := dest.WriteAt(srcBuffer[:chunk], destStart+offset); err != nil {
+			return diff, err
+		}
+	}
+	return diff, nil
+}
+
+// isValidFilesystem verifica que la partición tenga un superbloque coherente y un
+// inodo raíz de tipo carpeta; con ella ResyncMirror decide en qué sentido copiar
+func isValidFilesystem(file *os.File, start, size int64) bool {
+	sb := &SuperBlockDisk{}
+	if _, err := file.Seek(start, 0); err != nil {
+		return false
+	}
+	if err := binary.Read(file, binary.LittleEndian, sb); err != nil {
+		return false
+	}
+	if !isPlausibleSuperBlock(sb, 0, size) {
+		return false
+	}
+
+	if _, err := file.Seek(start+int64(sb.SInodeStart)+2*int64(sb.SInodeSize), 0); err != nil {
+		return false
+	}
+	root, err := readInodeFromDisc(file)
+	return err == nil && root.IType == INODE_FOLDER && root.IBlock[0] >= 0
+}
+
+// MountMirroredPartition monta la partición name de diskPath con su espejo en mirrorPath.
+// Retorna el ID, la advertencia de revisión de MountPartition y el estado inicial del
+// espejo: sincronizado si ambas copias son iguales, desactualizado si no.
+func MountMirroredPartition(diskPath, mirrorPath, name string, readOnly bool) (string, string, string, error) {
+	if diskLockKey(diskPath) == diskLockKey(mirrorPath) {
+		return "", "", "", fmt.Errorf("el disco espejo debe ser distinto al disco de la partición")
+	}
+	for _, mp := range utils.MountedPartitions {
+		if mp.UsesPartition(mirrorPath, name) {
+			return "", "", "", fmt.Errorf("la partición '%s' del disco espejo está en uso por la partición montada con ID %s", name, mp.ID)
+		}
+	}
+
+	// El espejo se compara, valida y repara sobre la zona de datos sin descifrar, así
+	// que una partición cifrada no se puede espejar
+	for _, path := range []string{diskPath, mirrorPath} {
+		if encrypted, _ := IsPartitionEncrypted(path, name); encrypted {
+			return "", "", "", fmt.Errorf("la partición '%s' de %s está cifrada, no se puede montar con espejo", name, path)
+		}
+	}
+
+	pair, err := openMirrorPair(diskPath, mirrorPath, name, os.O_RDONLY)
+	if err != nil {
+		return "", "", "", err
+	}
+	diff, err := syncRegion(pair.primary, pair.primaryRegion.Start, pair.mirror, pair.mirrorRegion.Start, pair.primaryRegion.Size, false)
+	pair.Close()
+	if err != nil {
+		return "", "", "", fmt.Errorf("error comparando la partición con su espejo: %v", err)
+	}
+
+	id, warning, err := MountPartition(diskPath, name, readOnly)
+	if err != nil {
+		return id, "", "", err
+	}
+
+	mp, err := FindMountedPartitionById(id)
+	if err != nil {
+		return "", "", "", err
+	}
+	mp.MirrorPath = mirrorPath
+	mp.MirrorStatus = MIRROR_STALE
+	if diff == 0 {
+		mp.MirrorStatus = MIRROR_SYNCED
+		// El montaje solo actualizó el superbloque de la copia principal
+		if !readOnly {
+			syncSuperBlockOrDegrade(mp)
+		}
+	}
+	fmt.Printf("Debug: Partición %s montada con espejo en %s (%s, %d bytes distintos)\n", id, mirrorPath, mp.MirrorStatus, diff)
+
+	saveStateOrWarn()
+	return id, warning, mp.MirrorStatus, nil
+}
+
+// syncSuperBlockOrDegrade copia al espejo el superbloque de la copia principal. Si no se
+// puede, la partición sigue funcionando con una sola copia y el espejo queda degradado.
+func syncSuperBlockOrDegrade(mp *utils.MountedPartition) {
+	pair, err := openMirrorPair(mp.DiskPath, mp.MirrorPath, mp.PartitionName, os.O_RDWR)
+	if err == nil {
+		_, err = syncRegion(pair.primary, pair.primaryRegion.Start, pair.mirror, pair.mirrorRegion.Start, SUPERBLOCK_SIZE, true)
+		pair.Close()
+	}
+	if err != nil {
+		fmt.Printf("Advertencia: no se pudo escribir el espejo %s de la partición %s, queda degradado: %v\n", mp.MirrorPath, mp.ID, err)
+		mp.MirrorStatus = MIRROR_DEGRADED
+	}
+}
+
+// setMirrorStatus cambia el estado del espejo de la partición montada, si sigue montada
+func setMirrorStatus(id, status string) {
+	mp, err := FindMountedPartitionById(id)
+	if err != nil || mp.MirrorStatus == status {
+		return
+	}
+	mp.MirrorStatus = status
+	saveStateOrWarn()
+}
+
+// lockMirroredPartition bloquea los dos discos de un volumen espejo con el modo de la
+// operación. Mientras se tengan en exclusivo, las lecturas pueden reparar la copia
+// principal desde el espejo.
+func lockMirroredPartition(mp *utils.MountedPartition, exclusive bool) (func(), error) {
+	unlock, err := lockDisksInOrder(mp.DiskPath, exclusive, mp.MirrorPath, exclusive)
+	if err != nil {
+		return nil, err
+	}
+	if !exclusive {
+		return unlock, nil
+	}
+
+	id := mp.ID
+	mirrorWritersMutex.Lock()
+	mirrorExclusive[id]++
+	mirrorWritersMutex.Unlock()
+	return func() {
+		mirrorWritersMutex.Lock()
+		if mirrorExclusive[id]--; mirrorExclusive[id] <= 0 {
+			delete(mirrorExclusive, id)
+		}
+		mirrorWritersMutex.Unlock()
+		unlock()
+	}, nil
+}
+
+// mirrorWriters cuenta por ID los MirroredPartition abiertos que ya escribieron. Mientras
+// haya alguno el estado guardado es desactualizado, aunque se escriban las dos copias.
+// mirrorExclusive cuenta los bloqueos exclusivos tomados con lockMirroredPartition.
+var (
+	mirrorWriters      = make(map[string]int)
+	mirrorExclusive    = make(map[string]int)
+	mirrorWritersMutex sync.Mutex
+)
+
+// MirroredPartition es la zona de datos de una partición montada con espejo, desde el
+// byte 0. Cada escritura va a las dos copias. Antes de la primera escritura el espejo se
+// guarda como desactualizado y vuelve a sincronizado al hacer Sync con ambas copias
+// escritas, así un corte a mitad de la operación deja pendiente un resync.
+//
+// Las lecturas usan la copia principal. Si el superbloque o un inodo de la principal no
+// pasa la validación y el del espejo sí, esa estructura se lee del espejo; solo se
+// reescribe en la principal si la operación tiene los discos en exclusivo y el montaje
+// no es de solo lectura.
+type MirroredPartition struct {
+	id                        string
+	primary, mirror           *os.File
+	primaryStart, mirrorStart int64
+	size                      int64
+	pos                       int64
+	writing                   bool // ya se guardó el espejo como desactualizado
+	degraded                  bool // falló el espejo, solo se escribe la copia principal
+	repair                    bool // se puede reescribir la principal desde el espejo
+	layout                    *SuperBlockDisk
+	layoutLoaded              bool
+	fromMirror                map[int64]bool // estructuras ya validadas, por posición: true si se leen del espejo
+}
+
+// openMirroredPartition abre las dos copias de una partición montada con espejo
+func openMirroredPartition(mp *utils.MountedPartition, flag int) (*MirroredPartition, error) {
+	pair, err := openMirrorPair(mp.DiskPath, mp.MirrorPath, mp.PartitionName, flag)
+	if err != nil {
+		return nil, err
+	}
+	mirrorWritersMutex.Lock()
+	exclusive := mirrorExclusive[mp.ID] > 0
+	mirrorWritersMutex.Unlock()
+
+	return &MirroredPartition{
+		id:           mp.ID,
+		primary:      pair.primary,
+		mirror:       pair.mirror,
+		primaryStart: pair.primaryRegion.Start,
+		mirrorStart:  pair.mirrorRegion.Start,
+		size:         pair.primaryRegion.Size,
+		repair:       exclusive && !mp.ReadOnly && flag&(os.O_WRONLY|os.O_RDWR) != 0,
+		fromMirror:   make(map[int64]bool),
+	}, nil
+}
+
+// useMirrorHandle indica si la partición se abre con sus dos copias: el espejo está
+// sincronizado, o está desactualizado solo porque otro MirroredPartition abierto escribe
+func useMirrorHandle(mp *utils.MountedPartition) bool {
+	if mp.MirrorStatus == MIRROR_SYNCED {
+		return true
+	}
+	mirrorWritersMutex.Lock()
+	defer mirrorWritersMutex.Unlock()
+	return mp.MirrorStatus == MIRROR_STALE && mirrorWriters[mp.ID] > 0
+}
+
+// Size retorna el tamaño de la zona de datos en bytes
+func (m *MirroredPartition) Size() int64 {
+	return m.size
+}
+
+// beginWrite guarda el espejo como desactualizado antes de la primera escritura
+func (m *MirroredPartition) beginWrite() {
+	if m.writing {
+		return
+	}
+	m.writing = true
+	mirrorWritersMutex.Lock()
+	mirrorWriters[m.id]++
+	mirrorWritersMutex.Unlock()
+	if mp, err := FindMountedPartitionById(m.id); err == nil && mp.MirrorStatus == MIRROR_SYNCED {
+		setMirrorStatus(m.id, MIRROR_STALE)
+	}
+}
+
+// endWrite vuelve el espejo a sincronizado cuando ya no queda nadie escribiendo y
+// ninguna escritura del espejo falló
+func (m *MirroredPartition) endWrite() {
+	if !m.writing {
+		return
+	}
+	m.writing = false
+	mirrorWritersMutex.Lock()
+	mirrorWriters[m.id]--
+	pending := mirrorWriters[m.id]
+	if pending == 0 {
+		delete(mirrorWriters, m.id)
+	}
+	mirrorWritersMutex.Unlock()
+
+	mp, err := FindMountedPartitionById(m.id)
+	if err == nil && pending == 0 && !m.degraded && mp.MirrorStatus == MIRROR_STALE {
+		setMirrorStatus(m.id, MIRROR_SYNCED)
+	}
+}
+
+// degrade deja de escribir el espejo; la partición sigue funcionando con la copia principal
+func (m *MirroredPartition) degrade(err error) {
+	fmt.Printf("Advertencia: no se pudo escribir el espejo de la partición %s, queda degradado: %v\n", m.id, err)
+	m.degraded = true
+	setMirrorStatus(m.id, MIRROR_DEGRADED)
+}
+
+func (m *MirroredPartition) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("posición negativa en la partición espejo: %d", off)
+	}
+	if off >= m.size {
+		return 0, io.EOF
+	}
+	chunk := p
+	if remaining := m.size - off; int64(len(chunk)) > remaining {
+		chunk = chunk[:remaining]
+	}
+	n, err := m.primary.ReadAt(chunk, m.primaryStart+off)
+	if err != nil && err != io.EOF && !m.degraded {
+		fmt.Printf("Advertencia: error leyendo la partición %s, se lee del espejo: %v\n", m.id, err)
+		n, err = m.mirror.ReadAt(chunk, m.mirrorStart+off)
+	}
+	if err != nil && err != io.EOF {
+		return n, err
+	}
+
+	if !m.degraded {
+		m.patchFromMirror(chunk[:n], off)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// patchFromMirror reemplaza en buf, leído de la principal desde off, las partes del
+// superbloque y de los inodos que se deben leer del espejo
+func (m *MirroredPartition) patchFromMirror(buf []byte, off int64) {
+	end := off + int64(len(buf))
+	if off < SUPERBLOCK_SIZE {
+		m.patchStructure(buf, off, 0, SUPERBLOCK_SIZE)
+	}
+
+	sb := m.fsLayout()
+	if sb == nil {
+		return
+	}
+	inodeSize := int64(sb.SInodeSize)
+	tableStart := int64(sb.SInodeStart)
+	tableEnd := tableStart + int64(sb.SInodesCount)*inodeSize
+	first, last := max(off, tableStart), tableEnd
+	if end < last {
+		last = end
+	}
+	for start := tableStart + (first-tableStart)/inodeSize*inodeSize; start < last; start += inodeSize {
+		m.patchStructure(buf, off, start, inodeSize)
+	}
+}
+
+// patchStructure copia a buf la parte de la estructura [start, start+length) que cae en
+// buf, leída del espejo, si la estructura se debe leer del espejo
+func (m *MirroredPartition) patchStructure(buf []byte, off, start, length int64) {
+	if !m.structureFromMirror(start, length) {
+		return
+	}
+	from, to := max(off, start), start+length
+	if end := off + int64(len(buf)); end < to {
+		to = end
+	}
+	if _, err := m.mirror.ReadAt(buf[from-off:to-off], m.mirrorStart+from); err != nil {
+		fmt.Printf("Advertencia: no se pudo leer el espejo de la partición %s: %v\n", m.id, err)
+	}
+}
+
+// structureFromMirror valida la estructura que empieza en start en la copia principal y,
+// si no es válida, en el espejo. El resultado se recuerda hasta que se escriba encima.
+func (m *MirroredPartition) structureFromMirror(start, length int64) bool {
+	if useMirror, checked := m.fromMirror[start]; checked {
+		return useMirror
+	}
+
+	useMirror := false
+	primary := make([]byte, length)
+	if _, err := m.primary.ReadAt(primary, m.primaryStart+start); err != nil || !m.validStructure(primary, start) {
+		mirror := make([]byte, length)
+		if _, err := m.mirror.ReadAt(mirror, m.mirrorStart+start); err == nil && m.validStructure(mirror, start) {
+			useMirror = true
+			fmt.Printf("Advertencia: la estructura en el byte %d de la partición %s no pasó la validación, se usa la copia del espejo\n", start, m.id)
+			if m.repair {
+				if _, err := m.primary.WriteAt(mirror, m.primaryStart+start); err != nil {
+					fmt.Printf("Advertencia: no se pudo reparar la partición %s desde el espejo: %v\n", m.id, err)
+				} else {
+					useMirror = false
+				}
+			}
+		}
+	}
+	m.fromMirror[start] = useMirror
+	return useMirror
+}
+
+// fsLayout retorna el superbloque válido de cualquiera de las dos copias, o nil si la
+// partición no tiene sistema de archivos
+func (m *MirroredPartition) fsLayout() *SuperBlockDisk {
+	if m.layoutLoaded {
+		return m.layout
+	}
+	m.layoutLoaded = true
+	for _, image := range []struct {
+		file  *os.File
+		start int64
+	}{{m.primary, m.primaryStart}, {m.mirror, m.mirrorStart}} {
+		buf := make([]byte, SUPERBLOCK_SIZE)
+		if _, err := image.file.ReadAt(buf, image.start); err != nil {
+			continue
+		}
+		sb := &SuperBlockDisk{}
+		if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, sb); err == nil && isPlausibleSuperBlock(sb, 0, m.size) {
+			m.layout = sb
+			break
+		}
+	}
+	return m.layout
+}
+
+// validStructure valida el superbloque (start 0) o un inodo de la tabla
+func (m *MirroredPartition) validStructure(buf []byte, start int64) bool {
+	if start == 0 {
+		sb := &SuperBlockDisk{}
+		if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, sb); err != nil {
+			return false
+		}
+		return isPlausibleSuperBlock(sb, 0, m.size)
+	}
+
+	// Mismo orden de campos que writeInodeToDisc
+	var inode struct {
+		IUid, IGid, ISize      int32
+		IPerm                  [3]byte
+		IAtime, ICtime, IMtime int64
+		IBlock                 [15]int32
+		IType                  byte
+	}
+	if err := binary.Read(bytes.NewReader(buf), binary.LittleEndian, &inode); err != nil {
+		return false
+	}
+	if (inode.IType != INODE_FOLDER && inode.IType != INODE_FILE) || inode.ISize < 0 {
+		return false
+	}
+	for _, block := range inode.IBlock {
+		if block < -1 || block >= m.layout.SBlocksCount {
+			return false
+		}
+	}
+	return true
+}
+
+// forgetStructures descarta la validación de las estructuras que toca una escritura
+func (m *MirroredPartition) forgetStructures(off, length int64) {
+	if off < SUPERBLOCK_SIZE {
+		m.fromMirror = make(map[int64]bool)
+		m.layout, m.layoutLoaded = nil, false
+		return
+	}
+	if m.layout == nil {
+		return
+	}
+	inodeSize := int64(m.layout.SInodeSize)
+	for start := range m.fromMirror {
+		if start < off+length && off < start+inodeSize {
+			delete(m.fromMirror, start)
+		}
+	}
+}
+
+func (m *MirroredPartition) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 || off+int64(len(p)) > m.size {
+		return 0, fmt.Errorf("escritura fuera de la partición espejo (posición %d, tamaño %d)", off, m.size)
+	}
+	m.beginWrite()
+	m.forgetStructures(off, int64(len(p)))
+	n, err := m.primary.WriteAt(p, m.primaryStart+off)
+	if err != nil {
+		return n, err
+	}
+	if !m.degraded {
+		if _, err := m.mirror.WriteAt(p, m.mirrorStart+off); err != nil {
+			m.degrade(err)
+		}
+	}
+	return n, nil
+}
+
+func (m *MirroredPartition) Read(p []byte) (int, error) {
+	n, err := m.ReadAt(p, m.pos)
+	m.pos += int64(n)
+	return n, err
+}
+
+func (m *MirroredPartition) Write(p []byte) (int, error) {
+	n, err := m.WriteAt(p, m.pos)
+	m.pos += int64(n)
+	return n, err
+}
+
+func (m *MirroredPartition) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += m.pos
+	case io.SeekEnd:
+		offset += m.size
+	default:
+		return m.pos, fmt.Errorf("whence inválido: %d", whence)
+	}
+	if offset < 0 {
+		return m.pos, fmt.Errorf("posición negativa en la partición espejo: %d", offset)
+	}
+	m.pos = offset
+	return m.pos, nil
+}
+
+// Sync baja a disco las dos copias; si ambas quedaron escritas el espejo vuelve a sincronizado
+func (m *MirroredPartition) Sync() error {
+	if err := m.primary.Sync(); err != nil {
+		return err
+	}
+	if !m.degraded {
+		if err := m.mirror.Sync(); err != nil {
+			m.degrade(err)
+		}
+	}
+	m.endWrite()
+	return nil
+}
+
+func (m *MirroredPartition) Close() error {
+	if m.writing {
+		m.Sync()
+	}
+	m.mirror.Close()
+	return m.primary.Close()
+}
+
+// ResyncMirror reconstruye el espejo de una partición montada. Normalmente se copia la
+// principal sobre el espejo; solo si la principal no es válida y el espejo sí se copia
+// en sentido contrario. Retorna la dirección de la copia y los bytes reescritos.
+func ResyncMirror(id string) (string, int64, error) {
+	mp, err := FindMountedPartitionById(id)
+	if err != nil {
+		return "", 0, err
+	}
+	if mp.MirrorPath == "" {
+		return "", 0, fmt.Errorf("la partición %s no está montada con espejo", id)
+	}
+
+	pair, err := openMirrorPair(mp.DiskPath, mp.MirrorPath, mp.PartitionName, os.O_RDWR)
+	if err != nil {
+		return "", 0, err
+	}
+	defer pair.Close()
+
+	size := pair.primaryRegion.Size
+	src, srcStart, dest, destStart := pair.primary, pair.primaryRegion.Start, pair.mirror, pair.mirrorRegion.Start
+	direction := fmt.Sprintf("%s -> %s", mp.DiskPath, mp.MirrorPath)
+	if !isValidFilesystem(pair.primary, pair.primaryRegion.Start, size) &&
+		isValidFilesystem(pair.mirror, pair.mirrorRegion.Start, size) {
+		if mp.ReadOnly {
+			return "", 0, fmt.Errorf("la partición %s está montada en modo solo lectura y la copia principal necesita repararse desde el espejo", id)
+		}
+		src, srcStart, dest, destStart = pair.mirror, pair.mirrorRegion.Start, pair.primary, pair.primaryRegion.Start
+		direction = fmt.Sprintf("%s -> %s", mp.MirrorPath, mp.DiskPath)
+	}
+
+	copied, err := syncRegion(src, srcStart, dest, destStart, size, true)
+	if err != nil {
+		mp.MirrorStatus = MIRROR_DEGRADED
+		saveStateOrWarn()
+		return "", copied, fmt.Errorf("error sincronizando el espejo: %v", err)
+	}
+
+	mp.MirrorStatus = MIRROR_SYNCED
+	saveStateOrWarn()
+	fmt.Printf("Debug: Espejo de %s sincronizado (%s, %d bytes)\n", id, direction, copied)
+	return direction, copied, nil
+}
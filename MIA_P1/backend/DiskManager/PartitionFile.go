@@ -0,0 +1,59 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"fmt"
+	"io"
+	"os"
+)
+
+// PartitionFile es lo que usa el código EXT2 para leer y escribir una partición montada:
+// un *os.File del disco (con la partición desde startByte), o un SpannedVolume,
+// MirroredPartition o EncryptedPartition (desde 0)
+type PartitionFile interface {
+	io.ReadWriteSeeker
+	io.ReaderAt
+	io.WriterAt
+	io.Closer
+	Sync() error
+}
+
+// OpenMountedPartition abre una partición montada para el código EXT2 y retorna el archivo,
+// el byte donde empieza el sistema de archivos y su tamaño. Para un volumen el archivo es
+// el SpannedVolume, para una partición con espejo sincronizado un MirroredPartition y para
+// una partición cifrada un EncryptedPartition; en esos casos el sistema de archivos
+// empieza en 0.
+func OpenMountedPartition(mp *utils.MountedPartition, flag int) (PartitionFile, int64, int64, error) {
+	if mp.PartitionType == PARTITION_VOLUME {
+		volume, err := openSpannedVolume(mp.VolumeParts, flag)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return volume, 0, volume.Size(), nil
+	}
+	if mp.MirrorPath != "" && useMirrorHandle(mp) {
+		mirrored, err := openMirroredPartition(mp, flag)
+		if err == nil {
+			return mirrored, 0, mirrored.Size(), nil
+		}
+		// Sin el espejo la partición sigue funcionando con la copia principal
+		fmt.Printf("Advertencia: no se pudo abrir el espejo de la partición %s, queda degradado: %v\n", mp.ID, err)
+		setMirrorStatus(mp.ID, MIRROR_DEGRADED)
+	}
+
+	file, err := os.OpenFile(mp.DiskPath, flag, 0666)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	startByte, size, err := GetPartitionDetails(file, mp)
+	if err != nil {
+		file.Close()
+		return nil, 0, 0, err
+	}
+	partitionFile, startByte, size, err := openEncryptedIfNeeded(file, mp.DiskPath, mp.PartitionName, startByte, size)
+	if err != nil {
+		file.Close()
+		return nil, 0, 0, err
+	}
+	return partitionFile, startByte, size, nil
+}
@@ -0,0 +1,300 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// DATA_DIR_ENV permite cambiar el directorio donde se guarda el estado del servidor
+	DATA_DIR_ENV     = "MIA_DATA_DIR"
+	DEFAULT_DATA_DIR = "data"
+	STATE_FILE_NAME  = "estado.json"
+)
+
+// ServerState es lo que se guarda en disco para sobrevivir reinicios del servidor
+type ServerState struct {
+	Disks   []DiskInfo               `json:"disks"`
+	Mounted []utils.MountedPartition `json:"mounted"`
+}
+
+var stateMutex = &sync.Mutex{}
+
+// GetDataDir retorna el directorio de datos configurado
+func GetDataDir() string {
+	if dir := strings.TrimSpace(os.Getenv(DATA_DIR_ENV)); dir != "" {
+		return dir
+	}
+	return DEFAULT_DATA_DIR
+}
+
+// getStateFilePath retorna la ruta completa del archivo de estado
+func getStateFilePath() string {
+	return filepath.Join(GetDataDir(), STATE_FILE_NAME)
+}
+
+// SaveState guarda el registro de discos y la tabla de montaje en el archivo de estado
+func SaveState() error {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	state := ServerState{
+		Disks:   GetAllDisks(),
+		Mounted: GetMountedPartitions(),
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializando estado: %v", err)
+	}
+
+	if err := os.MkdirAll(GetDataDir(), 0755); err != nil {
+		return fmt.Errorf("error creando directorio de datos: %v", err)
+	}
+
+	// Escribir en un temporal y renombrar para no dejar el archivo a medias
+	statePath := getStateFilePath()
+	tmpPath := statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("error escribiendo estado: %v", err)
+	}
+	if err := os.Rename(tmpPath, statePath); err != nil {
+		return fmt.Errorf("error reemplazando archivo de estado: %v", err)
+	}
+
+	return nil
+}
+
+// saveStateOrWarn guarda el estado y solo reporta el error, para no abortar la operación
+func saveStateOrWarn() {
+	if err := SaveState(); err != nil {
+		fmt.Printf("Advertencia: no se pudo guardar el estado: %v\n", err)
+	}
+}
+
+// LoadState rehidrata el registro de discos y la tabla de montaje desde el archivo de estado.
+// Se descartan los discos cuyo archivo .mia ya no existe, las particiones montadas que no
+// coinciden con el estado guardado en el MBR/EBR, las particiones cifradas (su clave solo
+// estaba en memoria) y los volúmenes a los que les falta una parte; las particiones
+// marcadas como montadas en disco que no aparecen en la tabla se marcan como desmontadas.
+func LoadState() error {
+	data, err := os.ReadFile(getStateFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("No existe archivo de estado en %s, se inicia sin discos registrados\n", getStateFilePath())
+			return nil
+		}
+		return fmt.Errorf("error leyendo estado: %v", err)
+	}
+
+	var state ServerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("error interpretando estado: %v", err)
+	}
+
+	// 1. Rehidratar discos que todavía existen
+	diskMutex.Lock()
+	for _, disk := range state.Disks {
+		if _, err := os.Stat(disk.Path); err != nil {
+			fmt.Printf("Estado: se descarta el disco %s, el archivo ya no existe\n", disk.Path)
+			continue
+		}
+		disks[disk.Path] = disk
+	}
+	diskMutex.Unlock()
+
+	// 2. Rehidratar particiones montadas comparando con el disco
+	mountedByDisk := make(map[string]map[string]bool)
+	keepMounted := func(diskPath, partitionName string) {
+		if mountedByDisk[diskPath] == nil {
+			mountedByDisk[diskPath] = make(map[string]bool)
+		}
+		mountedByDisk[diskPath][partitionName] = true
+	}
+	var mounted []utils.MountedPartition
+	for _, mp := range state.Mounted {
+		// Un volumen no figura en ningún MBR: basta con que sigan existiendo sus partes
+		if mp.PartitionType == PARTITION_VOLUME {
+			if err := checkVolumeParts(mp.VolumeParts); err != nil {
+				fmt.Printf("Estado: se descarta el volumen %s, %v\n", mp.ID, err)
+				continue
+			}
+			for _, part := range mp.VolumeParts {
+				keepMounted(part.DiskPath, part.PartitionName)
+			}
+			mounted = append(mounted, mp)
+			continue
+		}
+
+		if _, err := os.Stat(mp.DiskPath); err != nil {
+			fmt.Printf("Estado: se descarta el montaje %s, el disco %s ya no existe\n", mp.ID, mp.DiskPath)
+			continue
+		}
+
+		status, found, err := readPartitionStatus(mp.DiskPath, mp.PartitionName)
+		if err != nil || !found {
+			fmt.Printf("Estado: se descarta el montaje %s, la partición '%s' ya no existe\n", mp.ID, mp.PartitionName)
+			continue
+		}
+		if status != PARTITION_MOUNTED {
+			fmt.Printf("Estado: se descarta el montaje %s, la partición '%s' no está montada en disco\n", mp.ID, mp.PartitionName)
+			continue
+		}
+		// Sin la clave no se puede leer el sistema de archivos; hay que volver a montarla con -pass
+		if encrypted, _ := IsPartitionEncrypted(mp.DiskPath, mp.PartitionName); encrypted {
+			fmt.Printf("Estado: se descarta el montaje %s, la partición '%s' está cifrada y se debe montar de nuevo con -pass\n", mp.ID, mp.PartitionName)
+			continue
+		}
+
+		keepMounted(mp.DiskPath, mp.PartitionName)
+		mounted = append(mounted, mp)
+	}
+	utils.MountedPartitions = mounted
+
+	// 3. Limpiar particiones que quedaron marcadas como montadas sin estar en la tabla
+	for _, disk := range GetAllDisks() {
+		if err := resetStaleMountStatus(disk.Path, mountedByDisk[disk.Path]); err != nil {
+			fmt.Printf("Estado: no se pudo reconciliar el disco %s: %v\n", disk.Path, err)
+		}
+	}
+
+	fmt.Printf("Estado cargado: %d discos, %d particiones montadas\n", len(GetAllDisks()), len(mounted))
+
+	// Guardar el estado ya reconciliado
+	return SaveState()
+}
+
+// checkVolumeParts verifica que cada parte de un volumen siga existiendo en su disco
+func checkVolumeParts(parts []utils.VolumePart) error {
+	if len(parts) == 0 {
+		return fmt.Errorf("no tiene partes")
+	}
+	for _, part := range parts {
+		if _, err := os.Stat(part.DiskPath); err != nil {
+			return fmt.Errorf("el disco %s ya no existe", part.DiskPath)
+		}
+		if _, found, err := readPartitionStatus(part.DiskPath, part.PartitionName); err != nil || !found {
+			return fmt.Errorf("la partición '%s' de %s ya no existe", part.PartitionName, part.DiskPath)
+		}
+	}
+	return nil
+}
+
+// readPartitionStatus busca una partición primaria o lógica por nombre y retorna su estado
+func readPartitionStatus(diskPath, partitionName string) (byte, bool, error) {
+	file, err := os.OpenFile(diskPath, os.O_RDONLY, 0666)
+	if err != nil {
+		return 0, false, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	mbr := &MBR{}
+	if err := binary.Read(file, binary.LittleEndian, mbr); err != nil {
+		return 0, false, fmt.Errorf("error leyendo MBR: %v", err)
+	}
+
+	for _, p := range mbr.MbrPartitions {
+		if p.Size <= 0 {
+			continue
+		}
+		pName := strings.TrimRight(string(p.Name[:]), " \x00")
+		if pName == partitionName && p.Type == PARTITION_PRIMARY {
+			return p.Status, true, nil
+		}
+		if p.Type == PARTITION_EXTENDED {
+			ebrs, err := getLogicalPartitions(file, p.Start)
+			if err != nil {
+				continue
+			}
+			for _, ebr := range ebrs {
+				ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
+				if ebr.Size > 0 && ebrName == partitionName {
+					return ebr.Status, true, nil
+				}
+			}
+		}
+	}
+
+	return 0, false, nil
+}
+
+// resetStaleMountStatus marca como desmontadas las particiones del disco que no están en keep
+func resetStaleMountStatus(diskPath string, keep map[string]bool) error {
+	file, err := os.OpenFile(diskPath, os.O_RDWR, 0666)
+	if err != nil {
+		return fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	mbr := &MBR{}
+	if err := binary.Read(file, binary.LittleEndian, mbr); err != nil {
+		return fmt.Errorf("error leyendo MBR: %v", err)
+	}
+
+	mbrChanged := false
+	for i, p := range mbr.MbrPartitions {
+		if p.Size <= 0 {
+			continue
+		}
+		pName := strings.TrimRight(string(p.Name[:]), " \x00")
+
+		if p.Type == PARTITION_PRIMARY && p.Status == PARTITION_MOUNTED && !keep[pName] {
+			fmt.Printf("Estado: la partición '%s' de %s figuraba montada, se marca como desmontada\n", pName, diskPath)
+			mbr.MbrPartitions[i].Status = PARTITION_NOT_MOUNTED
+			mbr.MbrPartitions[i].Correlative = -1
+			mbrChanged = true
+		}
+
+		if p.Type != PARTITION_EXTENDED {
+			continue
+		}
+
+		// Revisar los EBRs de la extendida
+		currentPos := p.Start
+		visited := make(map[int64]bool)
+		for currentPos != -1 && !visited[currentPos] {
+			visited[currentPos] = true
+			if _, err := file.Seek(currentPos, 0); err != nil {
+				break
+			}
+			ebr := &EBR{}
+			if err := binary.Read(file, binary.LittleEndian, ebr); err != nil {
+				break
+			}
+
+			ebrName := strings.TrimRight(string(ebr.Name[:]), " \x00")
+			if ebr.Size > 0 && ebr.Status == PARTITION_MOUNTED && !keep[ebrName] {
+				fmt.Printf("Estado: la partición '%s' de %s figuraba montada, se marca como desmontada\n", ebrName, diskPath)
+				ebr.Status = PARTITION_NOT_MOUNTED
+				if _, err := file.Seek(currentPos, 0); err != nil {
+					return fmt.Errorf("error posicionando cursor para EBR: %v", err)
+				}
+				if err := binary.Write(file, binary.LittleEndian, ebr); err != nil {
+					return fmt.Errorf("error actualizando EBR: %v", err)
+				}
+			}
+
+			if ebr.Next < p.Start || ebr.Next >= p.Start+p.Size {
+				break
+			}
+			currentPos = ebr.Next
+		}
+	}
+
+	if mbrChanged {
+		if _, err := file.Seek(0, 0); err != nil {
+			return fmt.Errorf("error posicionando cursor para MBR: %v", err)
+		}
+		if err := binary.Write(file, binary.LittleEndian, mbr); err != nil {
+			return fmt.Errorf("error actualizando MBR: %v", err)
+		}
+	}
+
+	return nil
+}
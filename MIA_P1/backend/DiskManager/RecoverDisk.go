@@ -0,0 +1,462 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Tamaño de lectura usado al recorrer la imagen en busca de estructuras
+const RECOVERY_SCAN_CHUNK = 1024 * 1024
+
+// RecoveredPartition es una partición propuesta para la tabla reconstruida
+type RecoveredPartition struct {
+	Type      string `json:"type"` // "P", "E" o "L"
+	Name      string `json:"name"`
+	Start     int64  `json:"start"`
+	Size      int64  `json:"size"`
+	Source    string `json:"source"`    // estructura que permitió encontrarla
+	Estimated bool   `json:"estimated"` // true si el tamaño es una estimación
+}
+
+// DiskRecoveryPlan es la propuesta de tabla de particiones para un disco dañado
+type DiskRecoveryPlan struct {
+	Path       string               `json:"path"`
+	DiskSize   int64                `json:"diskSize"`
+	Partitions []RecoveredPartition `json:"partitions"`
+	Discarded  []string             `json:"discarded"`
+	mbr        *MBR
+}
+
+// superBlockCandidate es un superbloque encontrado durante el escaneo
+type superBlockCandidate struct {
+	pos int64
+	sb  SuperBlockDisk
+}
+
+// PlanDiskRecovery recorre la imagen buscando superbloques (SMagic == 0xEF53) y EBRs
+// válidos y propone una tabla MBR.MbrPartitions reconstruida. No modifica el disco.
+func PlanDiskRecovery(diskPath string) (*DiskRecoveryPlan, error) {
+	file, err := os.OpenFile(diskPath, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo información del archivo: %v", err)
+	}
+	diskSize := stat.Size()
+	mbrSize := int64(binary.Size(MBR{}))
+	if diskSize < mbrSize {
+		return nil, fmt.Errorf("el archivo es más pequeño que un MBR (%d bytes)", diskSize)
+	}
+
+	ebrs, superBlocks, err := scanDiskStructures(file, diskSize, mbrSize)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Debug: Escaneo de %s: %d EBRs y %d superbloques candidatos\n", diskPath, len(ebrs), len(superBlocks))
+
+	plan := &DiskRecoveryPlan{
+		Path:       diskPath,
+		DiskSize:   diskSize,
+		Partitions: []RecoveredPartition{},
+		Discarded:  []string{},
+	}
+	ebrSize := int64(binary.Size(EBR{}))
+
+	// 1. Elegir la cadena de EBRs que formará la extendida
+	chain := bestEBRChain(ebrs)
+	inChain := make(map[int64]bool)
+	for _, pos := range chain {
+		inChain[pos] = true
+	}
+	var outside []int64
+	for pos := range ebrs {
+		if !inChain[pos] {
+			outside = append(outside, pos)
+		}
+	}
+	sort.Slice(outside, func(i, j int) bool { return outside[i] < outside[j] })
+	for _, pos := range outside {
+		plan.Discarded = append(plan.Discarded,
+			fmt.Sprintf("EBR en %d fuera de la cadena principal (posible lógica eliminada)", pos))
+	}
+
+	var extStart, extEnd int64 = -1, -1
+	var logicals []RecoveredPartition
+	logicalData := make(map[int64]bool) // inicio de datos de cada lógica
+	for _, pos := range chain {
+		ebr := ebrs[pos]
+		end := pos + ebrSize
+		if ebr.Size > ebrSize {
+			end = pos + ebr.Size
+		}
+		if extStart == -1 {
+			extStart = pos
+		}
+		if end > extEnd {
+			extEnd = end
+		}
+		if ebr.Size > 0 {
+			logicals = append(logicals, RecoveredPartition{
+				Type:   "L",
+				Name:   strings.TrimRight(string(ebr.Name[:]), " \x00"),
+				Start:  pos,
+				Size:   ebr.Size,
+				Source: "EBR",
+			})
+			logicalData[pos+ebrSize] = true
+		}
+	}
+
+	// 2. Superbloques que no pertenecen a una lógica se proponen como primarias
+	var primaries []RecoveredPartition
+	var lastEnd int64
+	for i, cand := range superBlocks {
+		if logicalData[cand.pos] {
+			continue
+		}
+		if extStart != -1 && cand.pos >= extStart && cand.pos < extEnd {
+			plan.Discarded = append(plan.Discarded,
+				fmt.Sprintf("superbloque en %d dentro de la extendida sin EBR asociado", cand.pos))
+			continue
+		}
+		if cand.pos < lastEnd {
+			plan.Discarded = append(plan.Discarded,
+				fmt.Sprintf("superbloque en %d dentro de otra partición recuperada", cand.pos))
+			continue
+		}
+
+		// El límite es la siguiente estructura encontrada o el final del disco
+		limit := diskSize
+		for _, next := range superBlocks[i+1:] {
+			if !logicalData[next.pos] {
+				limit = next.pos
+				break
+			}
+		}
+		if extStart > cand.pos && extStart < limit {
+			limit = extStart
+		}
+
+		fsEnd := int64(cand.sb.SBlockStart) + int64(cand.sb.SBlocksCount)*int64(cand.sb.SBlockSize)
+		if cand.pos+fsEnd > limit {
+			plan.Discarded = append(plan.Discarded,
+				fmt.Sprintf("superbloque en %d describe un sistema de archivos que no cabe antes de %d", cand.pos, limit))
+			continue
+		}
+
+		size := estimatePartitionSize(fsEnd, limit-cand.pos)
+		primaries = append(primaries, RecoveredPartition{
+			Type:      "P",
+			Start:     cand.pos,
+			Size:      size,
+			Source:    "superbloque",
+			Estimated: true,
+		})
+		lastEnd = cand.pos + size
+	}
+
+	// 3. Armar la tabla: como máximo 4 slots entre primarias y extendida
+	var table []RecoveredPartition
+	table = append(table, primaries...)
+	if extStart != -1 {
+		table = append(table, RecoveredPartition{
+			Type:      "E",
+			Start:     extStart,
+			Size:      extEnd - extStart,
+			Source:    "EBR",
+			Estimated: true,
+		})
+	}
+	sort.Slice(table, func(i, j int) bool { return table[i].Start < table[j].Start })
+
+	// Si sobran, se conserva la extendida y las primarias con más espacio
+	maxSlots := len(MBR{}.MbrPartitions)
+	for len(table) > maxSlots {
+		smallest := -1
+		for i, p := range table {
+			if p.Type == "P" && (smallest == -1 || p.Size < table[smallest].Size) {
+				smallest = i
+			}
+		}
+		plan.Discarded = append(plan.Discarded,
+			fmt.Sprintf("primaria en %d, no hay slots libres en el MBR", table[smallest].Start))
+		table = append(table[:smallest], table[smallest+1:]...)
+	}
+
+	// Nombres para las particiones del MBR, cuyo nombre original se perdió
+	used := make(map[string]bool)
+	for _, l := range logicals {
+		used[l.Name] = true
+	}
+	primaryCount := 0
+	for i := range table {
+		prefix := "rec_ext"
+		if table[i].Type == "P" {
+			primaryCount++
+			prefix = fmt.Sprintf("rec_p%d", primaryCount)
+		}
+		name := prefix
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", prefix, n)
+		}
+		used[name] = true
+		table[i].Name = name
+	}
+
+	plan.mbr = buildRecoveredMBR(file, diskSize, table)
+	plan.Partitions = append(table, logicals...)
+
+	return plan, nil
+}
+
+// RecoverDisk vuelve a calcular la propuesta y escribe el MBR reconstruido
+func RecoverDisk(diskPath string) (*DiskRecoveryPlan, error) {
+	for _, mp := range utils.MountedPartitions {
+		if mp.UsesDisk(diskPath) {
+			return nil, fmt.Errorf("no se puede recuperar el disco: la partición '%s' está montada con ID %s", mp.PartitionName, mp.ID)
+		}
+	}
+
+	plan, err := PlanDiskRecovery(diskPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Partitions) == 0 {
+		return nil, fmt.Errorf("no se encontraron particiones para recuperar")
+	}
+
+	file, err := os.OpenFile(diskPath, os.O_RDWR, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	if err := binary.Write(file, binary.LittleEndian, plan.mbr); err != nil {
+		return nil, fmt.Errorf("error escribiendo MBR: %v", err)
+	}
+
+	fmt.Printf("Debug: MBR reconstruido en %s con %d particiones\n", diskPath, len(plan.Partitions))
+	return plan, nil
+}
+
+// scanDiskStructures recorre el disco por bloques y retorna los EBRs y superbloques plausibles
+func scanDiskStructures(file *os.File, diskSize, from int64) (map[int64]*EBR, []superBlockCandidate, error) {
+	ebrs := make(map[int64]*EBR)
+	var superBlocks []superBlockCandidate
+
+	ebrSize := int64(binary.Size(EBR{}))
+	sbSize := int64(binary.Size(SuperBlockDisk{}))
+	// Los bloques se solapan para no perder estructuras que crucen el borde
+	overlap := sbSize
+	buf := make([]byte, RECOVERY_SCAN_CHUNK+overlap)
+
+	for chunkStart := from; chunkStart < diskSize; chunkStart += RECOVERY_SCAN_CHUNK {
+		n, err := file.ReadAt(buf, chunkStart)
+		if err != nil && err != io.EOF {
+			return nil, nil, fmt.Errorf("error leyendo disco en %d: %v", chunkStart, err)
+		}
+		data := buf[:n]
+
+		limit := int64(n)
+		if limit > RECOVERY_SCAN_CHUNK {
+			limit = RECOVERY_SCAN_CHUNK
+		}
+		for i := int64(0); i < limit; i++ {
+			pos := chunkStart + i
+
+			if i+ebrSize <= int64(n) {
+				if ebr := parseEBRCandidate(data[i:i+ebrSize], pos, diskSize); ebr != nil {
+					ebrs[pos] = ebr
+				}
+			}
+
+			// SMagic está a 40 bytes del inicio del superbloque
+			if i+sbSize <= int64(n) && data[i+40] == 0x53 && data[i+41] == 0xEF &&
+				data[i+42] == 0 && data[i+43] == 0 {
+				var sb SuperBlockDisk
+				if err := binary.Read(bytes.NewReader(data[i:i+sbSize]), binary.LittleEndian, &sb); err == nil &&
+					isPlausibleSuperBlock(&sb, pos, diskSize) {
+					superBlocks = append(superBlocks, superBlockCandidate{pos: pos, sb: sb})
+				}
+			}
+		}
+	}
+
+	return ebrs, superBlocks, nil
+}
+
+// parseEBRCandidate decodifica un EBR y lo retorna solo si su contenido es coherente con su posición
+func parseEBRCandidate(data []byte, pos, diskSize int64) *EBR {
+	// Descarte rápido: estado, ajuste y Start deben coincidir antes de decodificar
+	if (data[0] != PARTITION_NOT_MOUNTED && data[0] != PARTITION_MOUNTED) || !isValidFit(data[1]) ||
+		int64(binary.LittleEndian.Uint64(data[2:10])) != pos {
+		return nil
+	}
+
+	ebr := &EBR{}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, ebr); err != nil {
+		return nil
+	}
+
+	ebrSize := int64(len(data))
+	if ebr.Size < 0 || (ebr.Size > 0 && ebr.Size < ebrSize) || pos+ebr.Size > diskSize {
+		return nil
+	}
+	if ebr.Next != -1 && (ebr.Next < pos+ebrSize || ebr.Next < pos+ebr.Size || ebr.Next+ebrSize > diskSize) {
+		return nil
+	}
+	// Un EBR sin lógica solo tiene sentido como cabeza de una cadena
+	if ebr.Size == 0 && ebr.Next == -1 {
+		return nil
+	}
+
+	// El nombre debe ser texto imprimible seguido de ceros
+	name := bytes.TrimRight(ebr.Name[:], "\x00")
+	if ebr.Size > 0 && len(name) == 0 {
+		return nil
+	}
+	for _, b := range name {
+		if b < 0x20 || b == 0x7f {
+			return nil
+		}
+	}
+
+	return ebr
+}
+
+// isPlausibleSuperBlock verifica que los desplazamientos del superbloque sean coherentes con mkfs
+func isPlausibleSuperBlock(sb *SuperBlockDisk, pos, diskSize int64) bool {
+	if sb.SMagic != EXT2_MAGIC || sb.SInodesCount <= 0 || sb.SBlocksCount <= 0 ||
+		sb.SInodeSize != INODE_SIZE || sb.SBlockSize != BLOCK_SIZE {
+		return false
+	}
+	if sb.SFreeInodesCount < 0 || sb.SFreeInodesCount > sb.SInodesCount ||
+		sb.SFreeBlocksCount < 0 || sb.SFreeBlocksCount > sb.SBlocksCount {
+		return false
+	}
+	// En EXT2 el bitmap de inodos va justo después del superbloque; en EXT3 después del journal
+	switch sb.SFilesystemType {
+	case EXT2_FILESYSTEM_TYPE:
+		if sb.SBmInodeStart != SUPERBLOCK_SIZE {
+			return false
+		}
+	case EXT3_FILESYSTEM_TYPE:
+		if sb.SBmInodeStart != SUPERBLOCK_SIZE+sb.SInodesCount*JOURNAL_ENTRY_SIZE {
+			return false
+		}
+	default:
+		return false
+	}
+	if sb.SBmBlockStart < sb.SBmInodeStart+sb.SInodesCount ||
+		sb.SInodeStart < sb.SBmBlockStart+sb.SBlocksCount ||
+		int64(sb.SBlockStart) < int64(sb.SInodeStart)+int64(sb.SInodesCount)*int64(sb.SInodeSize) {
+		return false
+	}
+	fsEnd := int64(sb.SBlockStart) + int64(sb.SBlocksCount)*int64(sb.SBlockSize)
+	return pos+fsEnd <= diskSize
+}
+
+// bestEBRChain retorna la cadena enlazada con más lógicas; en empate gana la que empieza antes
+func bestEBRChain(ebrs map[int64]*EBR) []int64 {
+	referenced := make(map[int64]bool)
+	for _, ebr := range ebrs {
+		if ebr.Next != -1 {
+			referenced[ebr.Next] = true
+		}
+	}
+
+	var heads []int64
+	for pos := range ebrs {
+		if !referenced[pos] {
+			heads = append(heads, pos)
+		}
+	}
+	sort.Slice(heads, func(i, j int) bool { return heads[i] < heads[j] })
+
+	var best []int64
+	bestLogicals := 0
+	for _, head := range heads {
+		var chain []int64
+		logicals := 0
+		visited := make(map[int64]bool)
+		for pos := head; pos != -1 && !visited[pos]; {
+			ebr, ok := ebrs[pos]
+			if !ok {
+				break
+			}
+			visited[pos] = true
+			chain = append(chain, pos)
+			if ebr.Size > 0 {
+				logicals++
+			}
+			pos = ebr.Next
+		}
+		if logicals > bestLogicals {
+			best, bestLogicals = chain, logicals
+		}
+	}
+
+	return best
+}
+
+// estimatePartitionSize estima el tamaño original a partir del final del sistema de archivos.
+// mkfs deja menos de un grupo (inodo + 3 bloques) sin usar al final, y fdisk crea tamaños
+// múltiplos de 1K o 1M, así que se busca el múltiplo más grande que encaje en ese margen.
+func estimatePartitionSize(fsEnd, maxSize int64) int64 {
+	group := int64(1 + 3 + INODE_SIZE + 3*BLOCK_SIZE)
+	upper := fsEnd + group - 1
+	if upper > maxSize {
+		upper = maxSize
+	}
+	for _, unit := range []int64{1024 * 1024, 1024} {
+		if candidate := (upper / unit) * unit; candidate >= fsEnd {
+			return candidate
+		}
+	}
+	return fsEnd
+}
+
+// buildRecoveredMBR arma el MBR conservando fecha y firma del original si siguen legibles
+func buildRecoveredMBR(file *os.File, diskSize int64, table []RecoveredPartition) *MBR {
+	old := &MBR{}
+	if _, err := file.Seek(0, 0); err == nil {
+		binary.Read(file, binary.LittleEndian, old)
+	}
+
+	mbr := &MBR{
+		MbrTamanio:       diskSize,
+		MbrFechaCreacion: old.MbrFechaCreacion,
+		MbrDskSignature:  old.MbrDskSignature,
+		DskFit:           old.DskFit,
+	}
+	if !isValidFit(mbr.DskFit) {
+		mbr.DskFit = FIT_FIRST
+	}
+
+	for i := range mbr.MbrPartitions {
+		mbr.MbrPartitions[i] = emptyPartitionSlot()
+	}
+	for i, rp := range table {
+		p := NewPartition()
+		p.Type = PARTITION_PRIMARY
+		if rp.Type == "E" {
+			p.Type = PARTITION_EXTENDED
+		}
+		p.Start = rp.Start
+		p.Size = rp.Size
+		copy(p.Name[:], rp.Name)
+		mbr.MbrPartitions[i] = p
+	}
+
+	return mbr
+}
@@ -0,0 +1,95 @@
+package DiskManager
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// ResizeDisk cambia el tamaño total del disco (1K = 1000 bytes, igual que mkdisk).
+// Al crecer, el espacio nuevo queda como hueco del archivo y se lee como ceros.
+// No se permite reducir por debajo del final de la última partición o EBR.
+// Retorna el tamaño anterior y el nuevo en bytes.
+func ResizeDisk(diskPath string, size int, unit string) (int64, int64, error) {
+	var newSize int64
+	switch unit {
+	case "K":
+		newSize = int64(size) * 1000
+	case "M":
+		newSize = int64(size) * 1000 * 1000
+	default:
+		return 0, 0, fmt.Errorf("unidad no válida: %s", unit)
+	}
+
+	pm, err := NewPartitionManager(diskPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	oldSize := pm.mbr.MbrTamanio
+
+	file, err := os.OpenFile(diskPath, os.O_RDWR, 0666)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	usedEnd := pm.usedDiskEnd(file)
+	if newSize < usedEnd {
+		return 0, 0, fmt.Errorf("no se puede reducir el disco a %d bytes: el último dato ocupado termina en el byte %d",
+			newSize, usedEnd)
+	}
+
+	if err := file.Truncate(newSize); err != nil {
+		return 0, 0, fmt.Errorf("error cambiando el tamaño del archivo: %v", err)
+	}
+
+	pm.mbr.MbrTamanio = newSize
+	if _, err := file.Seek(0, 0); err != nil {
+		return 0, 0, fmt.Errorf("error posicionando cursor para MBR: %v", err)
+	}
+	if err := binary.Write(file, binary.LittleEndian, pm.mbr); err != nil {
+		return 0, 0, fmt.Errorf("error escribiendo MBR: %v", err)
+	}
+
+	registeredSize, registeredUnit := diskSizeAndUnit(newSize)
+	updateDiskSize(diskPath, registeredSize, registeredUnit)
+
+	fmt.Printf("Debug: Disco %s redimensionado de %d a %d bytes\n", diskPath, oldSize, newSize)
+	return oldSize, newSize, nil
+}
+
+// usedDiskEnd retorna el byte donde termina lo último ocupado del disco:
+// el MBR, las particiones del MBR o los EBRs y lógicas de la extendida
+func (pm *PartitionManager) usedDiskEnd(file *os.File) int64 {
+	ebrSize := int64(binary.Size(EBR{}))
+	end := int64(binary.Size(pm.mbr))
+
+	for i := range pm.mbr.MbrPartitions {
+		p := &pm.mbr.MbrPartitions[i]
+		if p.Size <= 0 {
+			continue
+		}
+		if p.Start+p.Size > end {
+			end = p.Start + p.Size
+		}
+		if p.Type != PARTITION_EXTENDED {
+			continue
+		}
+
+		// El primer EBR siempre ocupa su lugar aunque la extendida esté vacía
+		if p.Start+ebrSize > end {
+			end = p.Start + ebrSize
+		}
+		for _, entry := range pm.readLogicalChain(file, p) {
+			entryEnd := entry.pos + ebrSize
+			if entry.ebr.Size > ebrSize {
+				entryEnd = entry.pos + entry.ebr.Size
+			}
+			if entryEnd > end {
+				end = entryEnd
+			}
+		}
+	}
+
+	return end
+}
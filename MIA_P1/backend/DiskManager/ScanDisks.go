@@ -0,0 +1,165 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiskScanResult describe el resultado de revisar un archivo .mia encontrado en el host
+type DiskScanResult struct {
+	Path       string `json:"path"`
+	Valid      bool   `json:"valid"`
+	Registered bool   `json:"registered"` // true si se agregó al registro en este escaneo
+	Reason     string `json:"reason,omitempty"`
+}
+
+// ScanDisks busca imágenes .mia en un directorio del host, valida su MBR y registra
+// las válidas con RegisterDisk. Si recursive es true también revisa los subdirectorios.
+func ScanDisks(dir string, recursive bool) ([]DiskScanResult, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("no se puede acceder al directorio '%s': %v", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("la ruta '%s' no es un directorio", dir)
+	}
+
+	var results []DiskScanResult
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Directorios sin permiso de lectura se omiten sin detener el escaneo
+			fmt.Printf("Debug: No se pudo revisar %s: %v\n", path, err)
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !strings.HasSuffix(strings.ToLower(d.Name()), ".mia") {
+			return nil
+		}
+
+		result := DiskScanResult{Path: path}
+		unlock, err := LockDisk(path, DISK_LOCK_SHARED)
+		if err != nil {
+			result.Reason = err.Error()
+			results = append(results, result)
+			return nil
+		}
+		mbr, err := ValidateDiskImage(path)
+		unlock()
+		if err != nil {
+			result.Reason = err.Error()
+			results = append(results, result)
+			return nil
+		}
+		result.Valid = true
+
+		if DiskExists(path) {
+			result.Reason = "el disco ya estaba registrado"
+		} else {
+			name, _ := utils.ExtractDiskInfo(path)
+			size, unit := diskSizeAndUnit(mbr.MbrTamanio)
+			RegisterDisk(DiskInfo{
+				Path: path,
+				Name: name,
+				Size: size,
+				Unit: unit,
+				Fit:  mbr.DskFit,
+			})
+			result.Registered = true
+		}
+
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return results, fmt.Errorf("error recorriendo el directorio: %v", err)
+	}
+
+	return results, nil
+}
+
+// ValidateDiskImage verifica que un archivo tenga un MBR coherente con su contenido
+func ValidateDiskImage(path string) (*MBR, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo disco: %v", err)
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo información del archivo: %v", err)
+	}
+
+	mbr := &MBR{}
+	mbrSize := int64(binary.Size(mbr))
+	if stat.Size() < mbrSize {
+		return nil, fmt.Errorf("el archivo es más pequeño que un MBR (%d bytes)", stat.Size())
+	}
+
+	if err := binary.Read(file, binary.LittleEndian, mbr); err != nil {
+		return nil, fmt.Errorf("error leyendo MBR: %v", err)
+	}
+
+	// El tamaño registrado debe coincidir con el tamaño real del archivo
+	if mbr.MbrTamanio != stat.Size() {
+		return nil, fmt.Errorf("el tamaño del MBR (%d bytes) no coincide con el del archivo (%d bytes)",
+			mbr.MbrTamanio, stat.Size())
+	}
+
+	if !isValidFit(mbr.DskFit) {
+		return nil, fmt.Errorf("ajuste del disco inválido: %d", mbr.DskFit)
+	}
+
+	extendidas := 0
+	for i, p := range mbr.MbrPartitions {
+		if p.Size == 0 {
+			continue
+		}
+		if p.Size < 0 || p.Start < mbrSize || p.Start+p.Size > mbr.MbrTamanio {
+			return nil, fmt.Errorf("la partición %d está fuera de los límites del disco (Start=%d, Size=%d)",
+				i+1, p.Start, p.Size)
+		}
+		if p.Type != PARTITION_PRIMARY && p.Type != PARTITION_EXTENDED {
+			return nil, fmt.Errorf("la partición %d tiene un tipo inválido: %d", i+1, p.Type)
+		}
+		if !isValidFit(p.Fit) {
+			return nil, fmt.Errorf("la partición %d tiene un ajuste inválido: %d", i+1, p.Fit)
+		}
+		if p.Type == PARTITION_EXTENDED {
+			extendidas++
+		}
+	}
+	if extendidas > 1 {
+		return nil, fmt.Errorf("el disco tiene %d particiones extendidas", extendidas)
+	}
+
+	return mbr, nil
+}
+
+// isValidFit indica si el byte corresponde a uno de los ajustes conocidos
+func isValidFit(fit byte) bool {
+	return fit == FIT_FIRST || fit == FIT_BEST || fit == FIT_WORST
+}
+
+// diskSizeAndUnit expresa el tamaño en bytes con las unidades que usa mkdisk (1K = 1000 bytes)
+func diskSizeAndUnit(totalBytes int64) (int, string) {
+	if totalBytes%(1000*1000) == 0 {
+		return int(totalBytes / (1000 * 1000)), "M"
+	}
+	return int(totalBytes / 1000), "K"
+}
@@ -38,16 +38,7 @@ func (pf *PartitionFit) FindPartitionSpace(partition *Partition) error {
 		return fmt.Errorf("no hay espacios libres disponibles")
 	}
 
-	var selectedSpace *Space
-	switch partition.Fit {
-	case FIT_BEST:
-		selectedSpace = pf.bestFit(spaces, partition.Size)
-	case FIT_WORST:
-		selectedSpace = pf.worstFit(spaces, partition.Size)
-	default:
-		selectedSpace = pf.firstFit(spaces, partition.Size)
-	}
-
+	selectedSpace := pf.selectSpace(spaces, partition.Size, partition.Fit)
 	if selectedSpace == nil {
 		return fmt.Errorf("no se encontró espacio suficiente")
 	}
@@ -102,82 +93,93 @@ func (pf *PartitionFit) getLastUsedPosition() int64 {
 }
 
 func (pf *PartitionFit) getFreeSpaces(mbr *MBR) []Space {
-	var spaces []Space
 	mbrSize := int64(binary.Size(mbr))
 
-	// Estructura para mantener track de los espacios reservados
-	type reservedSpace struct {
-		start int64
-		end   int64
-		inUse bool
-	}
-
-	var reserved []reservedSpace
-
-	// 1. Agregar el MBR como espacio reservado
-	reserved = append(reserved, reservedSpace{
-		start: 0,
-		end:   mbrSize,
-		inUse: true,
-	})
+	// Espacios reservados por las particiones
+	var reserved []Space
 
 	fmt.Printf("\n=== DEBUG: Espacios Reservados ===\n")
 	fmt.Printf("MBR: start=0, end=%d\n", mbrSize)
 
-	// 2. Procesar todas las particiones (activas e inactivas)
+	// 1. Procesar todas las particiones (activas e inactivas)
 	for i, p := range mbr.MbrPartitions {
 		if p.Size > 0 { // Si tiene tamaño asignado, está reservado
-			reserved = append(reserved, reservedSpace{
+			reserved = append(reserved, Space{
 				start: p.Start,
-				end:   p.Start + p.Size,
-				inUse: p.Status == PARTITION_MOUNTED,
+				size:  p.Size,
 			})
 			fmt.Printf("Partición %d: start=%d, end=%d, activa=%v\n",
 				i+1, p.Start, p.Start+p.Size, p.Status == PARTITION_MOUNTED)
 		}
 	}
 
-	// 3. Ordenar espacios reservados
-	sort.Slice(reserved, func(i, j int) bool {
-		return reserved[i].start < reserved[j].start
-	})
-
-	// 4. Encontrar espacios verdaderamente libres
-	lastEnd := mbrSize
+	// 2. Los espacios libres van desde el final del MBR hasta el final del disco
 	fmt.Printf("\n=== DEBUG: Espacios Libres ===\n")
+	spaces := freeSpacesBetween(mbrSize, mbr.MbrTamanio, reserved)
+	for _, space := range spaces {
+		fmt.Printf("Libre: start=%d, size=%d\n", space.start, space.size)
+	}
 
-	for i := 1; i < len(reserved); i++ {
-		current := reserved[i]
+	return spaces
+}
+
+// freeSpacesBetween calcula los huecos libres dentro del rango [start, end) dados los
+// espacios ocupados. Sirve tanto para el disco completo como para una partición extendida.
+func freeSpacesBetween(start, end int64, reserved []Space) []Space {
+	var spaces []Space
 
+	// Ordenar espacios reservados
+	sorted := make([]Space, len(reserved))
+	copy(sorted, reserved)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start < sorted[j].start
+	})
+
+	lastEnd := start
+	for _, current := range sorted {
 		// Verificar si hay espacio entre la última posición y esta
 		if current.start > lastEnd {
-			space := Space{
-				start: lastEnd,
-				size:  current.start - lastEnd,
+			gapEnd := current.start
+			if gapEnd > end {
+				gapEnd = end
+			}
+			if gapEnd > lastEnd {
+				spaces = append(spaces, Space{
+					start: lastEnd,
+					size:  gapEnd - lastEnd,
+				})
 			}
-			spaces = append(spaces, space)
-			fmt.Printf("Libre: start=%d, size=%d\n", space.start, space.size)
 		}
 
 		// Actualizar última posición solo si es mayor
-		if current.end > lastEnd {
-			lastEnd = current.end
+		if current.start+current.size > lastEnd {
+			lastEnd = current.start + current.size
 		}
 	}
 
-	// 5. Verificar espacio libre al final del disco
-	if lastEnd < mbr.MbrTamanio {
-		space := Space{
+	// Espacio libre al final del rango
+	if lastEnd < end {
+		spaces = append(spaces, Space{
 			start: lastEnd,
-			size:  mbr.MbrTamanio - lastEnd,
-		}
-		spaces = append(spaces, space)
-		fmt.Printf("Libre final: start=%d, size=%d\n", space.start, space.size)
+			size:  end - lastEnd,
+		})
 	}
 
 	return spaces
 }
 
+// selectSpace aplica el algoritmo de ajuste indicado sobre los espacios libres
+func (pf *PartitionFit) selectSpace(spaces []Space, size int64, fit byte) *Space {
+	switch fit {
+	case FIT_BEST:
+		return pf.bestFit(spaces, size)
+	case FIT_WORST:
+		return pf.worstFit(spaces, size)
+	default:
+		return pf.firstFit(spaces, size)
+	}
+}
+
 func (pf *PartitionFit) validatePartitionPosition(partition *Partition) error {
 	for i, p := range pf.mbr.MbrPartitions {
 		if p.Status == PARTITION_MOUNTED && p.Size > 0 {
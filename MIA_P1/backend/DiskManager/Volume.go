@@ -0,0 +1,246 @@
+package DiskManager
+
+import (
+	"MIA_P1/backend/utils"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// PARTITION_VOLUME es el tipo de montaje de un volumen creado con mkvolume
+const PARTITION_VOLUME = 'V'
+
+// volumeExtent es la zona de datos de una de las particiones que forman el volumen
+type volumeExtent struct {
+	file  *os.File
+	start int64 // inicio de la zona de datos en el disco
+	size  int64
+}
+
+// SpannedVolume concatena las zonas de datos de varias particiones, posiblemente en
+// discos distintos, en un solo espacio de direcciones que empieza en 0
+type SpannedVolume struct {
+	extents []volumeExtent
+	files   []*os.File // un archivo abierto por disco, aunque aporte varias particiones
+	size    int64
+	pos     int64
+}
+
+// openSpannedVolume abre los discos de las partes y ubica la zona de datos de cada una
+func openSpannedVolume(parts []utils.VolumePart, flag int) (*SpannedVolume, error) {
+	volume := &SpannedVolume{}
+	opened := make(map[string]*os.File)
+
+	for _, part := range parts {
+		key := diskLockKey(part.DiskPath)
+		file, exists := opened[key]
+		if !exists {
+			var err error
+			file, err = os.OpenFile(part.DiskPath, flag, 0666)
+			if err != nil {
+				volume.Close()
+				return nil, fmt.Errorf("error abriendo disco %s del volumen: %v", part.DiskPath, err)
+			}
+			opened[key] = file
+			volume.files = append(volume.files, file)
+		}
+
+		pm, err := NewPartitionManager(part.DiskPath)
+		if err != nil {
+			volume.Close()
+			return nil, err
+		}
+		region, err := pm.findDataRegion(file, part.PartitionName)
+		if err != nil {
+			volume.Close()
+			return nil, fmt.Errorf("%s: %v", part.DiskPath, err)
+		}
+
+		volume.extents = append(volume.extents, volumeExtent{file: file, start: region.Start, size: region.Size})
+		volume.size += region.Size
+	}
+	return volume, nil
+}
+
+// Size retorna el tamaño total del volumen en bytes
+func (v *SpannedVolume) Size() int64 {
+	return v.size
+}
+
+// access lee o escribe p en la posición off del volumen, repartiendo entre las partes
+func (v *SpannedVolume) access(p []byte, off int64, write bool) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("posición negativa en el volumen: %d", off)
+	}
+
+	done := 0
+	base := int64(0)
+	for _, extent := range v.extents {
+		if done == len(p) {
+			break
+		}
+		current := off + int64(done)
+		if current >= base+extent.size {
+			base += extent.size
+			continue
+		}
+
+		chunk := p[done:]
+		if remaining := base + extent.size - current; int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		var n int
+		var err error
+		if write {
+			n, err = extent.file.WriteAt(chunk, extent.start+current-base)
+		} else {
+			n, err = extent.file.ReadAt(chunk, extent.start+current-base)
+		}
+		done += n
+		if err != nil {
+			return done, err
+		}
+		base += extent.size
+	}
+
+	if done < len(p) {
+		if write {
+			return done, fmt.Errorf("escritura fuera del volumen (posición %d, tamaño %d)", off+int64(done), v.size)
+		}
+		return done, io.EOF
+	}
+	return done, nil
+}
+
+func (v *SpannedVolume) ReadAt(p []byte, off int64) (int, error) {
+	return v.access(p, off, false)
+}
+
+func (v *SpannedVolume) WriteAt(p []byte, off int64) (int, error) {
+	return v.access(p, off, true)
+}
+
+func (v *SpannedVolume) Read(p []byte) (int, error) {
+	n, err := v.ReadAt(p, v.pos)
+	v.pos += int64(n)
+	return n, err
+}
+
+func (v *SpannedVolume) Write(p []byte) (int, error) {
+	n, err := v.WriteAt(p, v.pos)
+	v.pos += int64(n)
+	return n, err
+}
+
+func (v *SpannedVolume) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += v.pos
+	case io.SeekEnd:
+		offset += v.size
+	default:
+		return v.pos, fmt.Errorf("whence inválido: %d", whence)
+	}
+	if offset < 0 {
+		return v.pos, fmt.Errorf("posición negativa en el volumen: %d", offset)
+	}
+	v.pos = offset
+	return v.pos, nil
+}
+
+func (v *SpannedVolume) Sync() error {
+	for _, file := range v.files {
+		if err := file.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *SpannedVolume) Close() error {
+	var firstErr error
+	for _, file := range v.files {
+		if err := file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// lockVolume bloquea todos los discos de un volumen
+func lockVolume(mp *utils.MountedPartition, exclusive bool) (func(), error) {
+	paths := make([]string, 0, len(mp.VolumeParts))
+	for _, part := range mp.VolumeParts {
+		paths = append(paths, part.DiskPath)
+	}
+	return LockDisks(paths, exclusive)
+}
+
+// CreateVolume une las particiones montadas con los IDs de partIDs, en ese orden, en un
+// volumen llamado name. Las particiones se desmontan y el volumen se monta con un ID propio;
+// si las partes ya formaban un volumen formateado en el mismo orden, su sistema de
+// archivos vuelve a quedar disponible.
+func CreateVolume(name string, partIDs []string) (*utils.MountedPartition, error) {
+	if len(partIDs) < 2 {
+		return nil, fmt.Errorf("un volumen necesita al menos dos particiones")
+	}
+	for _, mp := range utils.MountedPartitions {
+		if mp.PartitionType == PARTITION_VOLUME && strings.EqualFold(mp.PartitionName, name) {
+			return nil, fmt.Errorf("ya existe un volumen montado con el nombre '%s' (ID %s)", name, mp.ID)
+		}
+	}
+
+	var parts []utils.VolumePart
+	seen := make(map[string]bool)
+	for _, id := range partIDs {
+		mp, err := FindMountedPartitionById(id)
+		if err != nil {
+			return nil, err
+		}
+		if seen[mp.ID] {
+			return nil, fmt.Errorf("la partición %s está repetida en el volumen", mp.ID)
+		}
+		seen[mp.ID] = true
+
+		switch {
+		case mp.PartitionType == PARTITION_VOLUME:
+			return nil, fmt.Errorf("%s es un volumen, no se puede usar como parte de otro", mp.ID)
+		case mp.MirrorPath != "":
+			return nil, fmt.Errorf("la partición %s está montada con espejo, no se puede usar en un volumen", mp.ID)
+		case mp.ReadOnly:
+			return nil, fmt.Errorf("la partición %s está montada en modo solo lectura", mp.ID)
+		}
+		if encrypted, _ := IsPartitionEncrypted(mp.DiskPath, mp.PartitionName); encrypted {
+			return nil, fmt.Errorf("la partición %s está cifrada, no se puede usar en un volumen", mp.ID)
+		}
+		parts = append(parts, utils.VolumePart{DiskPath: mp.DiskPath, PartitionName: mp.PartitionName})
+	}
+
+	// El ID se asigna antes de desmontar las partes para que no reutilice el de ninguna
+	id, letter, number := utils.GenerateUniqueID(parts[0].DiskPath)
+	for _, partID := range partIDs {
+		if err := UnmountPartition(partID); err != nil {
+			return nil, fmt.Errorf("error desmontando la partición %s: %v", partID, err)
+		}
+	}
+
+	volume := utils.MountedPartition{
+		ID:            id,
+		DiskPath:      parts[0].DiskPath,
+		PartitionName: name,
+		PartitionType: PARTITION_VOLUME,
+		Status:        PARTITION_MOUNTED,
+		Letter:        letter,
+		Number:        number,
+		VolumeParts:   parts,
+	}
+
+	updateSuperBlockMountOrWarn(&volume)
+	utils.MountedPartitions = append(utils.MountedPartitions, volume)
+	saveStateOrWarn()
+
+	fmt.Printf("Debug: Volumen '%s' montado con ID %s (%d partes)\n", name, id, len(parts))
+	return FindMountedPartitionById(id)
+}
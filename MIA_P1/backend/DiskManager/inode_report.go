@@ -1,6 +1,7 @@
 package DiskManager
 
 import (
+	"MIA_P1/backend/utils"
 	"bytes"
 	"encoding/binary"
 	"fmt"
@@ -29,13 +30,14 @@ type SuperBlockDisk struct {
 	SBmBlockStart    int32     // Inicio del bitmap de bloques
 	SInodeStart      int32     // Inicio de la tabla de inodos
 	SBlockStart      int32     // Inicio de la tabla de bloques
-	SPadding         [808]byte // Padding para asegurar un tamaño total de 1024 bytes
+	SLastCheck       int64     // Timestamp Unix de la última revisión
+	SPadding         [800]byte // Padding para asegurar un tamaño total de 1024 bytes
 }
 
 // GenerateInodeReport genera un reporte gráfico de los inodos utilizados
-func GenerateInodeReport(diskPath string, partitionStartByte int64, outputPath string) (string, error) {
-	// 1. Abrir el archivo de disco
-	file, err := os.Open(diskPath)
+func GenerateInodeReport(mp *utils.MountedPartition, outputPath string) (string, error) {
+	// 1. Abrir la partición (o el volumen) y ubicar su inicio
+	file, partitionStartByte, _, err := OpenMountedPartition(mp, os.O_RDONLY)
 	if err != nil {
 		return "", fmt.Errorf("error abriendo el disco: %w", err)
 	}
@@ -341,7 +343,7 @@ func permissionToRWX(p byte) string {
 }
 
 // readInodeFromDisk lee un inodo desde la posición actual del archivo
-func readInodeFromDisk(file *os.File, inodeSize int) (*Inode, error) {
+func readInodeFromDisk(file PartitionFile, inodeSize int) (*Inode, error) {
 	inode := &Inode{}
 
 	// Leer campos en orden preciso
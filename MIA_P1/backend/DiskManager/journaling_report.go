@@ -0,0 +1,107 @@
+package DiskManager
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// JOURNAL_REPORT_CONTENT es cuánto contenido se muestra por operación en el reporte
+const JOURNAL_REPORT_CONTENT = 60
+
+// JournalingReporter genera un reporte gráfico con las operaciones del journal en una tabla
+func JournalingReporter(id, path string) (bool, string) {
+	// 1. Leer las operaciones del journal
+	operations, err := GetJournal(id)
+	if err != nil {
+		return false, fmt.Sprintf("Error: %s", err)
+	}
+
+	// 2. Generar el DOT para Graphviz
+	var dot strings.Builder
+	dot.WriteString("digraph Journaling {\n")
+	dot.WriteString("  node [shape=plaintext, fontname=\"Arial\"];\n")
+	dot.WriteString("  bgcolor=\"white\";\n")
+	dot.WriteString("  labelloc=\"t\";\n")
+	dot.WriteString("  fontname=\"Arial\";\n")
+	dot.WriteString("  fontsize=20;\n")
+	dot.WriteString(fmt.Sprintf("  label=\"Reporte de Journaling - Partición %s\";\n\n", id))
+
+	dot.WriteString("  journal [label=<\n")
+	dot.WriteString("    <TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\" CELLPADDING=\"4\">\n")
+	dot.WriteString("      <TR>\n")
+	for _, header := range []string{"#", "Operación", "Ruta", "Contenido", "Propietario", "Permisos", "Fecha"} {
+		dot.WriteString(fmt.Sprintf("        <TD BGCOLOR=\"#00796B\"><FONT COLOR=\"white\"><B>%s</B></FONT></TD>\n", header))
+	}
+	dot.WriteString("      </TR>\n")
+
+	if len(operations) == 0 {
+		dot.WriteString("      <TR><TD COLSPAN=\"7\">El journal no tiene operaciones registradas</TD></TR>\n")
+	}
+	for i, op := range operations {
+		bg := ""
+		if i%2 == 1 {
+			bg = " BGCOLOR=\"#E0F2F1\""
+		}
+
+		// El contenido largo se recorta; los saltos de línea se muestran como \n
+		content := strings.ReplaceAll(op.Content, "\n", "\\n")
+		if runes := []rune(content); len(runes) > JOURNAL_REPORT_CONTENT {
+			content = fmt.Sprintf("%s... (%d bytes)", string(runes[:JOURNAL_REPORT_CONTENT]), len(op.Content))
+		}
+		owner, perms := "-", "-"
+		if op.Owner != "" {
+			owner = op.Owner + ":" + op.Group
+			perms = fmt.Sprintf("%d%d%d", op.Perms[0], op.Perms[1], op.Perms[2])
+		}
+		opPath := op.Path
+		if opPath == "" {
+			opPath = "-"
+		}
+
+		dot.WriteString("      <TR>\n")
+		for _, cell := range []string{
+			fmt.Sprintf("%d", op.Count),
+			op.Operation,
+			opPath,
+			content,
+			owner,
+			perms,
+			op.Date.Format("2006-01-02 15:04:05"),
+		} {
+			dot.WriteString(fmt.Sprintf("        <TD%s ALIGN=\"LEFT\">%s</TD>\n", bg, html.EscapeString(cell)))
+		}
+		dot.WriteString("      </TR>\n")
+	}
+
+	dot.WriteString("    </TABLE>\n")
+	dot.WriteString("  >];\n")
+	dot.WriteString("}\n")
+
+	// 3. Guardar el DOT
+	dotFile := path + ".dot"
+	if err := os.WriteFile(dotFile, []byte(dot.String()), 0644); err != nil {
+		return false, fmt.Sprintf("Error al escribir archivo DOT: %s", err)
+	}
+
+	// 4. Generar imagen con el formato de la extensión (png por defecto)
+	format := strings.TrimPrefix(filepath.Ext(path), ".")
+	if format == "" {
+		format = "png"
+	}
+	cmd := exec.Command("dot", "-T"+format, dotFile, "-o", path)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return false, fmt.Sprintf("Error al ejecutar Graphviz: %v\nStdout: %s\nStderr: %s\nArchivo DOT guardado en: %s",
+			err, stdout.String(), stderr.String(), dotFile)
+	}
+
+	return true, fmt.Sprintf("Reporte de journaling generado exitosamente en: %s (%d operaciones)", path, len(operations))
+}
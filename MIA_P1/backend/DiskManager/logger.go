@@ -50,20 +50,13 @@ func LogEXT2(id string) {
 	}
 
 	// 2. Abrir el disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDONLY, 0666)
+	file, startByte, size, err := OpenMountedPartition(mountedPartition, os.O_RDONLY)
 	if err != nil {
 		fmt.Printf("Error al abrir disco: %s\n", err)
 		return
 	}
 	defer file.Close()
 
-	// 3. Obtener detalles de la partición
-	startByte, size, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		fmt.Printf("Error al obtener detalles de la partición: %s\n", err)
-		return
-	}
-
 	fmt.Printf("=== INFORMACIÓN EXT2 DE PARTICIÓN %s ===\n", id)
 	fmt.Printf("Nombre: %s\n", mountedPartition.PartitionName)
 	fmt.Printf("Tipo: %c\n", mountedPartition.PartitionType)
@@ -85,7 +78,7 @@ func LogEXT2(id string) {
 
 	// 5. Mostrar información del SuperBloque
 	fmt.Println("=== SUPERBLOQUE ===")
-	fmt.Printf("Tipo de sistema: %d (EXT2)\n", sb.SFilesystemType)
+	fmt.Printf("Tipo de sistema: %d (%s)\n", sb.SFilesystemType, FilesystemName(sb.SFilesystemType))
 	fmt.Printf("Número mágico: 0x%X\n", sb.SMagic)
 	fmt.Printf("Total inodos: %d\n", sb.SInodesCount)
 	fmt.Printf("Total bloques: %d\n", sb.SBlocksCount)
@@ -131,7 +124,7 @@ func LogEXT2(id string) {
 		for _, b := range inodeBitmapSample {
 			fmt.Printf("%08b ", b)
 		}
-		fmt.Println("\n")
+		fmt.Print("\n\n")
 	}
 
 	// 7. Leer y mostrar bitmap de bloques (primeros bytes)
@@ -156,7 +149,7 @@ func LogEXT2(id string) {
 		for _, b := range blockBitmapSample {
 			fmt.Printf("%08b ", b)
 		}
-		fmt.Println("\n")
+		fmt.Print("\n\n")
 	}
 
 	// 8. Leer y mostrar el inodo raíz (inodo 2)
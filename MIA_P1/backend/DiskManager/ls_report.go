@@ -21,17 +21,11 @@ func LSReporter(id string, reportPath string, dirPath string) (bool, string) {
 	fmt.Printf("Partición encontrada: %s en %s\n", mountedPartition.ID, mountedPartition.DiskPath)
 
 	// 2. Abrir el archivo del disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, fmt.Sprintf("Error al abrir disco: %v", err)
 	}
 	defer file.Close()
-
-	// 3. Obtener la posición de inicio de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, fmt.Sprintf("Error obteniendo detalles de partición: %v", err)
-	}
 	fmt.Printf("Inicio de partición: %d bytes\n", startByte)
 
 	// 4. Leer el superbloque
@@ -197,7 +191,7 @@ func LSReporter(id string, reportPath string, dirPath string) (bool, string) {
 }
 
 // readDirectoryBlockFromDisk lee un bloque de directorio del disco
-func readDirectoryBlockFromDisk(file *os.File, dirBlock *DirectoryBlock) error {
+func readDirectoryBlockFromDisk(file PartitionFile, dirBlock *DirectoryBlock) error {
 	// Leer cada campo de BContent secuencialmente
 	for i := 0; i < B_CONTENT_COUNT; i++ {
 		// Leer el nombre (array de B_NAME_SIZE bytes)
@@ -14,18 +14,16 @@ func EXT2UpdateFileOwnerAndPermissions(partitionID, path, owner, group string, p
 	if err != nil {
 		return err
 	}
+	if err := CheckWritable(partitionID); err != nil {
+		return err
+	}
 
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return fmt.Errorf("error al abrir el disco: %s", err)
 	}
 	defer file.Close()
 
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return fmt.Errorf("error al obtener detalles de la partición: %s", err)
-	}
-
 	superblock := &SuperBlock{}
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -82,18 +80,12 @@ func FileExists(id string, path string) (bool, error) {
 	}
 
 	// 2. Abrir el disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, err
 	}
 	defer file.Close()
 
-	// 3. Obtener la posición de inicio de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, err
-	}
-
 	// 4. Leer el superbloque
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -18,18 +18,12 @@ func SbReporter(id, path string) (bool, string) {
 	}
 
 	// 2. Abrir el disco
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, fmt.Sprintf("Error al abrir el disco: %s", err)
 	}
 	defer file.Close()
 
-	// 3. Obtener detalles de la partición
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, fmt.Sprintf("Error al obtener detalles de la partición: %s", err)
-	}
-
 	// 4. Leer el superbloque
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
@@ -75,11 +69,7 @@ func SbReporter(id, path string) (bool, string) {
 	addRow("ID de partición", mountedPartition.ID, false)
 
 	// Información del sistema de archivos
-	fsType := "EXT2"
-	if superblock.SFilesystemType == 1 {
-		fsType = "EXT3"
-	}
-	addRow("Tipo de sistema de archivos", fsType, true)
+	addRow("Tipo de sistema de archivos", FilesystemName(superblock.SFilesystemType), true)
 
 	// Inodos
 	inodeUsagePercent := float64(superblock.SInodesCount-superblock.SFreeInodesCount) * 100.0 / float64(superblock.SInodesCount)
@@ -116,6 +106,12 @@ func SbReporter(id, path string) (bool, string) {
 	addRow("Última fecha de desmontaje", unmountTime, true)
 	addRow("Número de veces montado", fmt.Sprintf("%d", superblock.SMntCount), false)
 
+	lastCheck := "----------"
+	if superblock.SLastCheck.Unix() > 0 {
+		lastCheck = superblock.SLastCheck.Format("2006-01-02 15:04:05")
+	}
+	addRow("Última revisión", lastCheck, true)
+
 	// Valor mágico
 	magicHex := fmt.Sprintf("0x%X", superblock.SMagic)
 	isValidMagic := superblock.SMagic == 0xEF53
@@ -123,7 +119,7 @@ func SbReporter(id, path string) (bool, string) {
 	if !isValidMagic {
 		magicStatus = "Inválido"
 	}
-	addRow("Valor mágico", fmt.Sprintf("%s (%s)", magicHex, magicStatus), true)
+	addRow("Valor mágico", fmt.Sprintf("%s (%s)", magicHex, magicStatus), false)
 
 	// Cerrar la tabla
 	dot.WriteString("    </TABLE>\n")
@@ -61,18 +61,12 @@ func TreeReporter(id, path string) (bool, string) {
 		return false, fmt.Sprintf("Error: %s", err)
 	}
 
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return false, fmt.Sprintf("Error al abrir el disco: %s", err)
 	}
 	defer file.Close()
 
-	// 2. Obtener inicio de la partición y leer superbloque
-	startByte, _, err := GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return false, fmt.Sprintf("Error al obtener detalles de la partición: %s", err)
-	}
-
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
 		return false, fmt.Sprintf("Error al posicionarse en el superbloque: %s", err)
@@ -1158,7 +1152,7 @@ func TreeReporter(id, path string) (bool, string) {
 }
 
 // ReadDirectoryBlockFromDisc lee un bloque de directorio correctamente
-func ReadDirectoryBlockFromDisc(file *os.File, blockSize int64) (*DirectoryBlock, error) {
+func ReadDirectoryBlockFromDisc(file PartitionFile, blockSize int64) (*DirectoryBlock, error) {
 	dirBlock := &DirectoryBlock{}
 
 	// Leer todo el bloque como bytes
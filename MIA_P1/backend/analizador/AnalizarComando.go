@@ -1,6 +1,7 @@
 package analizador
 
 import (
+	"MIA_P1/backend/DiskManager"
 	"fmt"
 	"net/http"
 
@@ -26,6 +27,28 @@ func AnalizarComando(c *gin.Context) {
 				case "sobreescribir":
 					return
 
+				case "recuperarDisco":
+					if confirmReq.Confirmar {
+						confirmarRecoverdisk(c, confirmReq)
+					} else {
+						c.JSON(http.StatusOK, gin.H{
+							"mensaje": "Recuperación cancelada, el disco no fue modificado",
+							"exito":   true,
+						})
+					}
+					return
+
+				case "desfragmentarDisco":
+					if confirmReq.Confirmar {
+						confirmarDefragdisk(c, confirmReq)
+					} else {
+						c.JSON(http.StatusOK, gin.H{
+							"mensaje": "Desfragmentación cancelada, el disco no fue modificado",
+							"exito":   true,
+						})
+					}
+					return
+
 				case "crearDirs":
 					if confirmReq.Confirmar {
 
@@ -72,8 +95,34 @@ func AnalizarComando(c *gin.Context) {
 		handleMount(c, comando)
 	case CMD_MOUNTED:
 		HandleMounted(c, comando)
+	case CMD_UNMOUNT:
+		HandleUnmount(c, comando)
+	case CMD_SCANDISKS:
+		HandleScandisks(c, comando)
+	case CMD_CHECKDISK:
+		HandleCheckdisk(c, comando)
+	case CMD_RECOVERDISK:
+		HandleRecoverdisk(c, comando)
+	case CMD_RESIZEDISK:
+		HandleResizedisk(c, comando)
+	case CMD_CLONEDISK:
+		HandleClonedisk(c, comando)
+	case CMD_COPYPART:
+		HandleCopypart(c, comando)
+	case CMD_DEFRAGDISK:
+		HandleDefragdisk(c, comando)
+	case CMD_RESYNC:
+		HandleResync(c, comando)
+	case CMD_MKVOLUME:
+		HandleMkvolume(c, comando)
 	case CMD_REP:
 		HandleRep(c, comando)
+	case CMD_BADBLOCK:
+		HandleBadblock(c, comando)
+	case CMD_LOSS:
+		HandleLoss(c, comando)
+	case CMD_RECOVERY:
+		HandleRecovery(c, comando)
 	case CMD_MKFS:
 		HandleMkfs(c, comando)
 	case CMD_LOGIN:
@@ -96,6 +145,12 @@ func AnalizarComando(c *gin.Context) {
 		HandleMkfile(c, comando)
 	case CMD_MKDIR:
 		HandleMkdir(c, comando)
+	case CMD_REMOVE:
+		HandleRemove(c, comando)
+	case CMD_EDIT:
+		HandleEdit(c, comando)
+	case CMD_RENAME:
+		HandleRename(c, comando)
 	case CMD_COMENTARIO:
 		c.JSON(http.StatusOK, gin.H{
 			"mensaje": "", // Mensaje vacío para no duplicar el comentario
@@ -119,3 +174,55 @@ func mostrarErrores(c *gin.Context, errores []Error) {
 		"exito":   false,
 	})
 }
+
+// bloquearDisco toma el candado del disco; si no se puede, responde con el error
+func bloquearDisco(c *gin.Context, diskPath string, exclusive bool) (func(), bool) {
+	liberar, err := DiskManager.LockDisk(diskPath, exclusive)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: %s", err),
+			"exito":   false,
+		})
+		return nil, false
+	}
+	return liberar, true
+}
+
+// bloquearOrigenDestino toma el disco origen para lectura y el destino para escritura
+func bloquearOrigenDestino(c *gin.Context, srcPath, destPath string) (func(), bool) {
+	liberar, err := DiskManager.LockDiskPair(srcPath, destPath)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: %s", err),
+			"exito":   false,
+		})
+		return nil, false
+	}
+	return liberar, true
+}
+
+// bloquearEspejo toma en exclusivo el disco de una partición y el de su espejo
+func bloquearEspejo(c *gin.Context, diskPath, mirrorPath string) (func(), bool) {
+	liberar, err := DiskManager.LockMirrorPair(diskPath, mirrorPath)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: %s", err),
+			"exito":   false,
+		})
+		return nil, false
+	}
+	return liberar, true
+}
+
+// bloquearParticion toma el candado del disco que contiene la partición montada
+func bloquearParticion(c *gin.Context, id string, exclusive bool) (func(), bool) {
+	liberar, err := DiskManager.LockMountedPartition(id, exclusive)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: %s", err),
+			"exito":   false,
+		})
+		return nil, false
+	}
+	return liberar, true
+}
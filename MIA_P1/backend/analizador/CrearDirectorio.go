@@ -73,6 +73,12 @@ func CrearDirectorio(c *gin.Context) {
 		}
 
 		// Crear el disco físicamente
+		liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_EXCLUSIVE)
+		if !ok {
+			return
+		}
+		defer liberar()
+
 		if err := DiskManager.CreateDisk(params); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"mensaje": fmt.Sprintf("Error al crear el disco: %s", err),
@@ -22,6 +22,12 @@ func handleFdisk(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	partitionManager, err := DiskManager.NewPartitionManager(params.Path)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -31,6 +37,52 @@ func handleFdisk(c *gin.Context, comando string) {
 		return
 	}
 
+	// Eliminación de partición
+	if params.Delete != "" {
+		if err := partitionManager.DeletePartition(params.Name, params.Delete); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": fmt.Sprintf("Error al eliminar la partición: %s", err),
+				"exito":   false,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje":    fmt.Sprintf("Partición eliminada exitosamente:\nNombre: %s\nModo: %s", params.Name, params.Delete),
+			"nombre":     params.Name,
+			"modo":       params.Delete,
+			"parametros": params,
+			"exito":      true,
+		})
+		return
+	}
+
+	// Redimensionamiento de partición
+	if params.Add != 0 {
+		if err := partitionManager.ResizePartition(params.Name, int64(params.Add), params.Unit); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": fmt.Sprintf("Error al redimensionar la partición: %s", err),
+				"exito":   false,
+			})
+			return
+		}
+
+		accion := "agregado"
+		cantidad := params.Add
+		if cantidad < 0 {
+			accion = "quitado"
+			cantidad = -cantidad
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje":    fmt.Sprintf("Partición redimensionada exitosamente:\nNombre: %s\nEspacio %s: %d%s", params.Name, accion, cantidad, params.Unit),
+			"nombre":     params.Name,
+			"add":        fmt.Sprintf("%d%s", params.Add, params.Unit),
+			"parametros": params,
+			"exito":      true,
+		})
+		return
+	}
+
 	partition := DiskManager.NewPartition()
 
 	// Asignar tipo usando constantes
@@ -85,8 +137,23 @@ func handleFdisk(c *gin.Context, comando string) {
 		return
 	}
 
+	// Una partición cifrada queda lista para montarse con -pass
+	if params.Pass != "" {
+		if err := DiskManager.EncryptPartition(params.Path, params.Name, params.Pass); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": fmt.Sprintf("La partición se creó pero no se pudo cifrar: %s", err),
+				"exito":   false,
+			})
+			return
+		}
+		DiskManager.ForgetPartitionKey(params.Path, params.Name)
+	}
+
 	mensaje := fmt.Sprintf("Partición creada exitosamente:\nNombre: %s\nTamaño: %d%s\nTipo: %s\nAjuste: %s",
 		params.Name, params.Size, params.Unit, params.Type, params.Fit)
+	if params.Pass != "" {
+		mensaje += "\nCifrada: sí"
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"mensaje":    mensaje,
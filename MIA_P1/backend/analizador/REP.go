@@ -22,16 +22,18 @@ type RepParams struct {
 
 // Constantes para los tipos de reportes válidos
 var validReportTypes = map[string]bool{
-	"mbr":      true,
-	"disk":     true,
-	"inode":    true,
-	"block":    true,
-	"bm_inode": true,
-	"bm_block": true,
-	"tree":     true,
-	"sb":       true,
-	"file":     true,
-	"ls":       true,
+	"mbr":        true,
+	"disk":       true,
+	"inode":      true,
+	"block":      true,
+	"bm_inode":   true,
+	"bm_block":   true,
+	"tree":       true,
+	"sb":         true,
+	"file":       true,
+	"ls":         true,
+	"badblocks":  true,
+	"journaling": true,
 }
 
 func AnalizarRep(comando string) (RepParams, []RepError, bool, bool, string) {
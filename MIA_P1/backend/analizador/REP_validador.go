@@ -45,6 +45,12 @@ func HandleRep(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearParticion(c, params.ID, DiskManager.DISK_LOCK_SHARED)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	// Generar el reporte según el tipo
 	var reportPath string
 	var reportErr error
@@ -56,18 +62,7 @@ func HandleRep(c *gin.Context, comando string) {
 	case "disk":
 		reportPath, reportErr = DiskManager.GenerateDiskReport(partitionInfo.DiskPath, params.Path)
 	case "inode":
-		// Obtener la posición de inicio de la partición
-		startByte, posErr := DiskManager.GetPartitionStartByte(partitionInfo.DiskPath, partitionInfo.PartitionName)
-		if posErr != nil {
-			c.JSON(http.StatusOK, gin.H{
-				"mensaje": fmt.Sprintf("Error obteniendo la posición de la partición: %s", posErr),
-				"exito":   false,
-			})
-			return
-		}
-
-		// Llamar a la función para generar reporte de inodos con la posición obtenida
-		reportPath, reportErr = DiskManager.GenerateInodeReport(partitionInfo.DiskPath, startByte, params.Path)
+		reportPath, reportErr = DiskManager.GenerateInodeReport(&partitionInfo, params.Path)
 	case "block":
 		// Llamar a la función de reporte de bloques directamente con el ID
 		success, mensaje := DiskManager.BlockReporter(params.ID, params.Path)
@@ -134,6 +129,29 @@ func HandleRep(c *gin.Context, comando string) {
 		}
 
 		reportErr = nil
+	case "journaling":
+		success, mensaje := DiskManager.JournalingReporter(params.ID, params.Path)
+		if !success {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": mensaje,
+				"exito":   false,
+			})
+			return
+		}
+		reportPath = params.Path
+		reportErr = nil
+	case "badblocks":
+		success, mensaje := DiskManager.BadBlocksReporter(params.ID, params.Path)
+		if !success {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": mensaje,
+				"exito":   false,
+			})
+			return
+		}
+		reportPath = strings.TrimSuffix(params.Path, filepath.Ext(params.Path)) + ".txt"
+		reportErr = nil
+		isTextReport = true
 	case "tree":
 		// Implementando el reporte de árbol de directorios
 		success, mensaje := DiskManager.TreeReporter(params.ID, params.Path)
@@ -45,6 +45,12 @@ func handleRmdisk(c *gin.Context, comando string) {
 	}
 
 	// Eliminar el archivo
+	liberar, ok := bloquearDisco(c, path, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	if err := os.Remove(path); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"mensaje": fmt.Sprintf("Error al eliminar el disco: %s", err),
@@ -0,0 +1,110 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BadblockParams contiene los parámetros para el comando BADBLOCK
+type BadblockParams struct {
+	Id    string
+	Block int32
+}
+
+// ValidarBadblock extrae y valida los parámetros del comando BADBLOCK
+func ValidarBadblock(comando string) (BadblockParams, []Error) {
+	var params BadblockParams
+	var errores []Error
+
+	idRegex := regexp.MustCompile(`(?i)-id=([^\s]+)`)
+	blockRegex := regexp.MustCompile(`(?i)-block=([^\s]+)`)
+
+	// Extraer ID (obligatorio)
+	if matches := idRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.Id = strings.Trim(matches[1], "\"")
+	} else {
+		errores = append(errores, Error{
+			Parametro: "id",
+			Mensaje:   "El parámetro id es obligatorio",
+		})
+	}
+
+	// Extraer Block (obligatorio)
+	if matches := blockRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		block, err := strconv.ParseInt(strings.Trim(matches[1], "\""), 10, 32)
+		if err != nil || block < 0 {
+			errores = append(errores, Error{
+				Parametro: "block",
+				Mensaje:   "El bloque debe ser un número entero no negativo",
+			})
+		}
+		params.Block = int32(block)
+	} else {
+		errores = append(errores, Error{
+			Parametro: "block",
+			Mensaje:   "El parámetro block es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// parseBadBlockList interpreta una lista de bloques separados por coma, sin repetidos
+func parseBadBlockList(value string) ([]int32, error) {
+	var blocks []int32
+	seen := make(map[int32]bool)
+	for _, part := range strings.Split(strings.Trim(value, "\""), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		block, err := strconv.ParseInt(part, 10, 32)
+		if err != nil || block < 0 {
+			return nil, fmt.Errorf("'%s' no es un número de bloque válido", part)
+		}
+		if !seen[int32(block)] {
+			seen[int32(block)] = true
+			blocks = append(blocks, int32(block))
+		}
+	}
+	return blocks, nil
+}
+
+// HandleBadblock procesa el comando BADBLOCK
+func HandleBadblock(c *gin.Context, comando string) {
+	params, errores := ValidarBadblock(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearParticion(c, params.Id, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	badBlocks, err := DiskManager.MarkBadBlock(params.Id, params.Block)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al marcar el bloque dañado: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	mensaje := fmt.Sprintf("Bloque %d marcado como dañado en la partición %s\nBloques dañados: %d",
+		params.Block, params.Id, len(badBlocks))
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":        mensaje,
+		"bloquesDanados": badBlocks,
+		"parametros":     params,
+		"exito":          true,
+	})
+}
@@ -26,6 +26,12 @@ func HandleCat(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_SHARED)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	// Procesar cada archivo
 	var resultContent strings.Builder
 	var filesProcessed int
@@ -0,0 +1,80 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// CheckdiskParams contiene los parámetros para el comando CHECKDISK
+type CheckdiskParams struct {
+	Path   string
+	Repair bool
+}
+
+// HandleCheckdisk procesa el comando CHECKDISK
+func HandleCheckdisk(c *gin.Context, comando string) {
+	var params CheckdiskParams
+	var errores []Error
+
+	// Expresiones regulares para extraer parámetros
+	pathRegex := regexp.MustCompile(`(?i)-path=("[^"]+"|[^\s]+)`)
+	repairRegex := regexp.MustCompile(`(?i)(^|\s)-repair(\s|$)`)
+
+	// Extraer Path (obligatorio)
+	pathMatches := pathRegex.FindStringSubmatch(comando)
+	if len(pathMatches) > 1 {
+		params.Path = strings.Trim(pathMatches[1], "\"")
+		if !strings.HasSuffix(strings.ToLower(params.Path), ".mia") {
+			errores = append(errores, Error{
+				Parametro: "path",
+				Mensaje:   "El archivo debe tener extensión .mia",
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+
+	// Bandera -repair (opcional)
+	params.Repair = repairRegex.MatchString(comando)
+
+	// Si hay errores, mostrarlos
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	result, err := DiskManager.CheckDisk(params.Path, params.Repair)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al revisar el disco: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	mensaje := fmt.Sprintf("Revisión de %s:\n", params.Path)
+	for _, f := range result.Findings {
+		linea := fmt.Sprintf("[%s] %s", f.Severity, f.Message)
+		if f.Partition != "" {
+			linea = fmt.Sprintf("[%s] %s: %s", f.Severity, f.Partition, f.Message)
+		}
+		if f.Repaired {
+			linea += " (reparado)"
+		}
+		mensaje += linea + "\n"
+	}
+	mensaje += fmt.Sprintf("Errores: %d, Advertencias: %d, Reparados: %d", result.Errors, result.Warnings, result.Repaired)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":   mensaje,
+		"resultado": result,
+		"exito":     true,
+	})
+}
@@ -36,6 +36,12 @@ func HandleChgrp(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	// Leer el archivo users.txt
 	content, err := DiskManager.EXT2FileOperation(CurrentSession.PartitionID, "/users.txt", DiskManager.FILE_READ, "")
 	if err != nil {
@@ -0,0 +1,107 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"MIA_P1/backend/utils"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ClonediskParams contiene los parámetros para el comando CLONEDISK
+type ClonediskParams struct {
+	Src  string
+	Dest string
+}
+
+// ValidarClonedisk extrae y valida los parámetros del comando CLONEDISK
+func ValidarClonedisk(comando string) (ClonediskParams, []Error) {
+	var params ClonediskParams
+	var errores []Error
+
+	srcRegex := regexp.MustCompile(`(?i)-src=("[^"]+"|[^\s]+)`)
+	destRegex := regexp.MustCompile(`(?i)-dest=("[^"]+"|[^\s]+)`)
+
+	// Extraer Src (obligatorio)
+	srcMatches := srcRegex.FindStringSubmatch(comando)
+	if len(srcMatches) > 1 {
+		params.Src = strings.Trim(srcMatches[1], "\"")
+		if !strings.HasSuffix(strings.ToLower(params.Src), ".mia") {
+			errores = append(errores, Error{
+				Parametro: "src",
+				Mensaje:   "El archivo debe tener extensión .mia",
+			})
+		} else if !utils.DiskExists(params.Src) {
+			errores = append(errores, Error{
+				Parametro: "src",
+				Mensaje:   fmt.Sprintf("No existe el disco: %s", params.Src),
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "src",
+			Mensaje:   "El parámetro src es obligatorio",
+		})
+	}
+
+	// Extraer Dest (obligatorio)
+	destMatches := destRegex.FindStringSubmatch(comando)
+	if len(destMatches) > 1 {
+		params.Dest = strings.Trim(destMatches[1], "\"")
+		if !strings.HasSuffix(strings.ToLower(params.Dest), ".mia") {
+			errores = append(errores, Error{
+				Parametro: "dest",
+				Mensaje:   "El archivo debe tener extensión .mia",
+			})
+		} else if rutaValida, mensaje, _ := utils.ValidarRuta(params.Dest); !rutaValida {
+			errores = append(errores, Error{
+				Parametro: "dest",
+				Mensaje:   mensaje,
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "dest",
+			Mensaje:   "El parámetro dest es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleClonedisk procesa el comando CLONEDISK
+func HandleClonedisk(c *gin.Context, comando string) {
+	params, errores := ValidarClonedisk(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearOrigenDestino(c, params.Src, params.Dest)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	mbr, err := DiskManager.CloneDisk(params.Src, params.Dest)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al clonar el disco: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	mensaje := fmt.Sprintf("Disco clonado exitosamente:\nOrigen: %s\nDestino: %s\nTamaño: %d bytes\nFirma nueva: %d",
+		params.Src, params.Dest, mbr.MbrTamanio, mbr.MbrDskSignature)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    mensaje,
+		"ruta":       params.Dest,
+		"firma":      mbr.MbrDskSignature,
+		"parametros": params,
+		"exito":      true,
+	})
+}
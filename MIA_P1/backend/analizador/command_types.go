@@ -11,8 +11,21 @@ const (
 	CMD_FDISK          CommandType = "fdisk"
 	CMD_MOUNT          CommandType = "mount"
 	CMD_MOUNTED        CommandType = "mounted"
+	CMD_UNMOUNT        CommandType = "unmount"
+	CMD_SCANDISKS      CommandType = "scandisks"
+	CMD_CHECKDISK      CommandType = "checkdisk"
+	CMD_RECOVERDISK    CommandType = "recoverdisk"
+	CMD_RESIZEDISK     CommandType = "resizedisk"
+	CMD_CLONEDISK      CommandType = "clonedisk"
+	CMD_COPYPART       CommandType = "copypart"
+	CMD_DEFRAGDISK     CommandType = "defragdisk"
+	CMD_RESYNC         CommandType = "resync"
+	CMD_MKVOLUME       CommandType = "mkvolume"
 	CMD_REP            CommandType = "rep"
 	CMD_MKFS           CommandType = "mkfs"
+	CMD_BADBLOCK       CommandType = "badblock"
+	CMD_LOSS           CommandType = "loss"
+	CMD_RECOVERY       CommandType = "recovery"
 	CMD_EXT2AUTOINJECT CommandType = "ext2autoinject"
 	CMD_LOGIN          CommandType = "login"
 	CMD_LOGOUT         CommandType = "logout"
@@ -24,6 +37,9 @@ const (
 	CMD_CHGRP          CommandType = "chgrp"
 	CMD_MKFILE         CommandType = "mkfile"
 	CMD_MKDIR          CommandType = "mkdir"
+	CMD_REMOVE         CommandType = "remove"
+	CMD_EDIT           CommandType = "edit"
+	CMD_RENAME         CommandType = "rename"
 	CMD_COMENTARIO     CommandType = "#comentario"
 )
 
@@ -44,10 +60,36 @@ func IdentificarComando(comando string) CommandType {
 		return CMD_MOUNTED
 	case strings.HasPrefix(comando, string(CMD_MOUNT)):
 		return CMD_MOUNT
+	case strings.HasPrefix(comando, string(CMD_UNMOUNT)):
+		return CMD_UNMOUNT
+	case strings.HasPrefix(comando, string(CMD_SCANDISKS)):
+		return CMD_SCANDISKS
+	case strings.HasPrefix(comando, string(CMD_CHECKDISK)):
+		return CMD_CHECKDISK
+	case strings.HasPrefix(comando, string(CMD_RECOVERDISK)):
+		return CMD_RECOVERDISK
+	case strings.HasPrefix(comando, string(CMD_RESIZEDISK)):
+		return CMD_RESIZEDISK
+	case strings.HasPrefix(comando, string(CMD_CLONEDISK)):
+		return CMD_CLONEDISK
+	case strings.HasPrefix(comando, string(CMD_COPYPART)):
+		return CMD_COPYPART
+	case strings.HasPrefix(comando, string(CMD_DEFRAGDISK)):
+		return CMD_DEFRAGDISK
+	case strings.HasPrefix(comando, string(CMD_RESYNC)):
+		return CMD_RESYNC
+	case strings.HasPrefix(comando, string(CMD_MKVOLUME)):
+		return CMD_MKVOLUME
 	case strings.HasPrefix(comando, string(CMD_REP)):
 		return CMD_REP
 	case strings.HasPrefix(comando, string(CMD_MKFS)): // Añadir esta línea
 		return CMD_MKFS
+	case strings.HasPrefix(comando, string(CMD_BADBLOCK)):
+		return CMD_BADBLOCK
+	case strings.HasPrefix(comando, string(CMD_LOSS)):
+		return CMD_LOSS
+	case strings.HasPrefix(comando, string(CMD_RECOVERY)):
+		return CMD_RECOVERY
 	case strings.HasPrefix(comando, string(CMD_EXT2AUTOINJECT)): // Nuevo caso
 		return CMD_EXT2AUTOINJECT
 	case strings.HasPrefix(comando, string(CMD_LOGIN)):
@@ -70,6 +112,12 @@ func IdentificarComando(comando string) CommandType {
 		return CMD_MKFILE
 	case strings.HasPrefix(comando, string(CMD_MKDIR)): // Nuevo caso
 		return CMD_MKDIR
+	case strings.HasPrefix(comando, string(CMD_REMOVE)):
+		return CMD_REMOVE
+	case strings.HasPrefix(comando, string(CMD_EDIT)):
+		return CMD_EDIT
+	case strings.HasPrefix(comando, string(CMD_RENAME)):
+		return CMD_RENAME
 	case strings.HasPrefix(comando, string(CMD_MKDISK)):
 		return CMD_MKDISK
 	default:
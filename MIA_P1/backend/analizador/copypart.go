@@ -0,0 +1,148 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"MIA_P1/backend/utils"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// CopypartParams contiene los parámetros para el comando COPYPART
+type CopypartParams struct {
+	SrcPath  string
+	Name     string
+	DestPath string
+	DestName string
+	Type     string
+}
+
+// ValidarCopypart extrae y valida los parámetros del comando COPYPART
+func ValidarCopypart(comando string) (CopypartParams, []Error) {
+	var params CopypartParams
+	var errores []Error
+
+	// -name se ancla al inicio del parámetro para no confundirlo con -destname
+	srcPathRegex := regexp.MustCompile(`(?i)-srcpath=("[^"]+"|[^\s]+)`)
+	nameRegex := regexp.MustCompile(`(?i)(?:^|\s)-name=("[^"]+"|[^\s]+)`)
+	destPathRegex := regexp.MustCompile(`(?i)-destpath=("[^"]+"|[^\s]+)`)
+	destNameRegex := regexp.MustCompile(`(?i)-destname=("[^"]+"|[^\s]+)`)
+	typeRegex := regexp.MustCompile(`(?i)-type=([^\s]+)`)
+
+	// Extraer rutas (obligatorias)
+	for _, ruta := range []struct {
+		parametro string
+		regex     *regexp.Regexp
+		destino   *string
+	}{
+		{"srcpath", srcPathRegex, &params.SrcPath},
+		{"destpath", destPathRegex, &params.DestPath},
+	} {
+		matches := ruta.regex.FindStringSubmatch(comando)
+		if len(matches) < 2 {
+			errores = append(errores, Error{
+				Parametro: ruta.parametro,
+				Mensaje:   fmt.Sprintf("El parámetro %s es obligatorio", ruta.parametro),
+			})
+			continue
+		}
+		*ruta.destino = strings.Trim(matches[1], "\"")
+		if !strings.HasSuffix(strings.ToLower(*ruta.destino), ".mia") {
+			errores = append(errores, Error{
+				Parametro: ruta.parametro,
+				Mensaje:   "El archivo debe tener extensión .mia",
+			})
+		} else if !utils.DiskExists(*ruta.destino) {
+			errores = append(errores, Error{
+				Parametro: ruta.parametro,
+				Mensaje:   fmt.Sprintf("No existe el disco: %s", *ruta.destino),
+			})
+		}
+	}
+
+	// Extraer nombres (obligatorios)
+	if matches := nameRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.Name = strings.Trim(matches[1], "\"")
+	} else {
+		errores = append(errores, Error{
+			Parametro: "name",
+			Mensaje:   "El parámetro name es obligatorio",
+		})
+	}
+
+	if matches := destNameRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.DestName = strings.Trim(matches[1], "\"")
+		if len(params.DestName) > 16 {
+			errores = append(errores, Error{
+				Parametro: "destname",
+				Mensaje:   "El nombre no puede exceder 16 caracteres",
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "destname",
+			Mensaje:   "El parámetro destname es obligatorio",
+		})
+	}
+
+	// Extraer Type (opcional, por defecto el de la partición original)
+	if matches := typeRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.Type = strings.ToUpper(strings.Trim(matches[1], "\""))
+		if params.Type != "P" && params.Type != "L" {
+			errores = append(errores, Error{
+				Parametro: "type",
+				Mensaje:   "El valor de type debe ser P o L",
+			})
+		}
+	}
+
+	return params, errores
+}
+
+// HandleCopypart procesa el comando COPYPART
+func HandleCopypart(c *gin.Context, comando string) {
+	params, errores := ValidarCopypart(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearOrigenDestino(c, params.SrcPath, params.DestPath)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	var partType byte
+	switch params.Type {
+	case "P":
+		partType = DiskManager.PARTITION_PRIMARY
+	case "L":
+		partType = DiskManager.PARTITION_LOGIC
+	}
+
+	partition, err := DiskManager.CopyPartition(params.SrcPath, params.Name, params.DestPath, params.DestName, partType)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al copiar la partición: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	tipo := "Primaria"
+	if partition.Type == DiskManager.PARTITION_LOGIC {
+		tipo = "Lógica"
+	}
+	mensaje := fmt.Sprintf("Partición copiada exitosamente:\nOrigen: %s (%s)\nDestino: %s (%s)\nTipo: %s\nInicio de datos: %d\nTamaño: %d bytes",
+		params.Name, params.SrcPath, params.DestName, params.DestPath, tipo, partition.Start, partition.Size)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    mensaje,
+		"nombre":     params.DestName,
+		"parametros": params,
+		"exito":      true,
+	})
+}
@@ -0,0 +1,131 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// DefragdiskParams contiene los parámetros para el comando DEFRAGDISK
+type DefragdiskParams struct {
+	Path string
+}
+
+// ValidarDefragdisk extrae y valida los parámetros del comando DEFRAGDISK
+func ValidarDefragdisk(comando string) (DefragdiskParams, []Error) {
+	var params DefragdiskParams
+	var errores []Error
+
+	pathRegex := regexp.MustCompile(`(?i)-path=("[^"]+"|[^\s]+)`)
+
+	// Extraer Path (obligatorio)
+	pathMatches := pathRegex.FindStringSubmatch(comando)
+	if len(pathMatches) > 1 {
+		params.Path = strings.Trim(pathMatches[1], "\"")
+		if !strings.HasSuffix(strings.ToLower(params.Path), ".mia") {
+			errores = append(errores, Error{
+				Parametro: "path",
+				Mensaje:   "El archivo debe tener extensión .mia",
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleDefragdisk muestra los movimientos que haría la desfragmentación (sin aplicarlos)
+// y pide confirmación antes de mover las particiones
+func HandleDefragdisk(c *gin.Context, comando string) {
+	params, errores := ValidarDefragdisk(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_SHARED)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	plan, err := DiskManager.PlanDiskDefrag(params.Path)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al analizar el disco: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	if len(plan.Moves) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje":   fmt.Sprintf("El disco %s ya está compactado, no hay particiones que mover", params.Path),
+			"resultado": plan,
+			"exito":     true,
+		})
+		return
+	}
+
+	mensaje := "Simulación de desfragmentación (el disco no ha sido modificado):\n" +
+		formatDefragPlan(plan) +
+		"¿Desea mover las particiones?"
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":              mensaje,
+		"resultado":            plan,
+		"exito":                true,
+		"requiereConfirmacion": true,
+		"tipoConfirmacion":     "desfragmentarDisco",
+		"comando":              comando,
+		"path":                 params.Path,
+	})
+}
+
+// confirmarDefragdisk mueve las particiones después de la confirmación del usuario
+func confirmarDefragdisk(c *gin.Context, confirmReq ConfirmationRequest) {
+	params, errores := ValidarDefragdisk(confirmReq.Comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	plan, err := DiskManager.DefragDisk(params.Path)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al desfragmentar el disco: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":   "Disco desfragmentado exitosamente:\n" + formatDefragPlan(plan),
+		"resultado": plan,
+		"exito":     true,
+	})
+}
+
+// formatDefragPlan arma el reporte de texto de los movimientos de la desfragmentación
+func formatDefragPlan(plan *DiskManager.DiskDefragPlan) string {
+	reporte := fmt.Sprintf("Disco: %s (%d bytes)\n", plan.Path, plan.DiskSize)
+	for _, m := range plan.Moves {
+		reporte += fmt.Sprintf("  [%s] %s: %d -> %d (%d bytes)\n", m.Type, m.Name, m.OldStart, m.NewStart, m.Size)
+	}
+	reporte += fmt.Sprintf("Huecos libres: %d, el más grande de %d bytes\n", plan.FreeGapsBefore, plan.LargestFreeBefore)
+	reporte += fmt.Sprintf("Espacio libre contiguo al terminar: %d bytes\n", plan.LargestFreeAfter)
+	return reporte
+}
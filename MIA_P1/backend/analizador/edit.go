@@ -0,0 +1,173 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// EditParams contiene los parámetros para el comando EDIT
+type EditParams struct {
+	Path      string
+	Contenido string // Archivo del disco local con el nuevo contenido
+	Append    bool   // Parámetro -append: agrega al final en lugar de reemplazar
+}
+
+// ValidarEdit valida los parámetros del comando EDIT
+func ValidarEdit(comando string) (EditParams, []Error) {
+	var params EditParams
+	var errores []Error
+
+	tokens := tokenizarComando(comando)
+
+	// Ignorar el primer token (edit)
+	for i := 1; i < len(tokens); i++ {
+		token := strings.TrimSpace(tokens[i])
+		if token == "" {
+			continue
+		}
+
+		if strings.ToLower(token) == "-append" {
+			params.Append = true
+			continue
+		}
+
+		parts := strings.SplitN(token, "=", 2)
+		paramName := strings.ToLower(strings.TrimPrefix(parts[0], "-"))
+		if !strings.HasPrefix(token, "-") || len(parts) < 2 {
+			errores = append(errores, Error{
+				Parametro: paramName,
+				Mensaje:   "Parámetro no reconocido para edit",
+			})
+			continue
+		}
+		paramValue := strings.Trim(parts[1], "\"")
+
+		switch paramName {
+		case "path":
+			params.Path = paramValue
+		case "contenido":
+			params.Contenido = paramValue
+			// Verificar si el archivo existe en el disco local
+			if _, err := os.Stat(paramValue); os.IsNotExist(err) {
+				errores = append(errores, Error{
+					Parametro: "contenido",
+					Mensaje:   fmt.Sprintf("El archivo '%s' no existe en el disco local", paramValue),
+				})
+			}
+		case "append":
+			errores = append(errores, Error{
+				Parametro: "append",
+				Mensaje:   "El parámetro append no debe tener un valor asignado",
+			})
+		default:
+			errores = append(errores, Error{
+				Parametro: paramName,
+				Mensaje:   "Parámetro no reconocido para edit",
+			})
+		}
+	}
+
+	if params.Path == "" {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+	if params.Contenido == "" {
+		errores = append(errores, Error{
+			Parametro: "contenido",
+			Mensaje:   "El parámetro contenido es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleEdit procesa el comando EDIT, que reemplaza el contenido de un archivo (o lo
+// agrega al final con -append) con el de un archivo del disco local
+func HandleEdit(c *gin.Context, comando string) {
+	if CurrentSession == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": "Error: No hay una sesión activa. Debe iniciar sesión primero.",
+			"exito":   false,
+		})
+		return
+	}
+
+	params, errores := ValidarEdit(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	path := normalizePath(params.Path)
+	if path == "/users.txt" {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": "Error: no se permite editar directamente users.txt por seguridad",
+			"exito":   false,
+		})
+		return
+	}
+
+	// Verificar que sea un archivo y que el usuario pueda escribirlo
+	inodeNum, inode, err := getFileInode(CurrentSession.PartitionID, path)
+	if err != nil || inodeNum < 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: El archivo '%s' no existe", path),
+			"exito":   false,
+		})
+		return
+	}
+	if inode.IType != DiskManager.INODE_FILE {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: '%s' no es un archivo regular", path),
+			"exito":   false,
+		})
+		return
+	}
+	if err := DiskManager.CheckFilePermissions(inode, DiskManager.PERM_WRITE); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: No tiene permisos de escritura para '%s': %s", path, err),
+			"exito":   false,
+		})
+		return
+	}
+
+	contenido, err := os.ReadFile(params.Contenido)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al leer el archivo: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	operation := DiskManager.FILE_WRITE
+	if params.Append {
+		operation = DiskManager.FILE_APPEND
+	}
+	resultado, err := DiskManager.EXT2FileOperation(CurrentSession.PartitionID, path, operation, string(contenido))
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al editar '%s': %s", path, err),
+			"exito":   false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    fmt.Sprintf("Archivo '%s' editado: %s", path, resultado),
+		"parametros": params,
+		"exito":      true,
+	})
+}
@@ -25,6 +25,23 @@ func AnalizarFdisk(comando string) (utils.PartitionConfig, []FdiskError, bool) {
 		return params, errores, false
 	}
 
+	// El valor de add puede ser negativo, así que se extrae antes que el resto
+	addRegex := regexp.MustCompile(`(?i)-add=(\S*)`)
+	hasAdd := false
+	if addMatch := addRegex.FindStringSubmatch(comando); addMatch != nil {
+		add, err := strconv.Atoi(strings.TrimSpace(addMatch[1]))
+		if err != nil || add == 0 {
+			errores = append(errores, FdiskError{
+				Parametro: "add",
+				Mensaje:   "El valor de add debe ser un número entero distinto de cero",
+			})
+		} else {
+			params.Add = add
+		}
+		hasAdd = true
+		comando = addRegex.ReplaceAllString(comando, "")
+	}
+
 	paramRegex := regexp.MustCompile(`-(\w+)=([^-]*(?:"[^"]*")?[^-]*)`)
 	matches := paramRegex.FindAllStringSubmatch(comando, -1)
 
@@ -133,11 +150,47 @@ func AnalizarFdisk(comando string) (utils.PartitionConfig, []FdiskError, bool) {
 			}
 			params.Name = value
 			hasName = true
+
+		case "pass":
+			value = strings.Trim(value, "\"")
+			if value == "" {
+				errores = append(errores, FdiskError{
+					Parametro: "pass",
+					Mensaje:   "La contraseña no puede estar vacía",
+				})
+				continue
+			}
+			params.Pass = value
+
+		case "delete":
+			valueDelete := strings.ToLower(value)
+			if valueDelete != "fast" && valueDelete != "full" {
+				errores = append(errores, FdiskError{
+					Parametro: "delete",
+					Mensaje:   "El valor de delete debe ser fast o full",
+				})
+			} else {
+				params.Delete = valueDelete
+			}
 		}
 	}
 
-	// Verificar parámetros obligatorios
-	if !hasSize {
+	if hasAdd && params.Delete != "" {
+		errores = append(errores, FdiskError{
+			Parametro: "add",
+			Mensaje:   "No se puede usar add y delete en el mismo comando",
+		})
+	}
+
+	if params.Pass != "" && (hasAdd || params.Delete != "" || params.Type == "E") {
+		errores = append(errores, FdiskError{
+			Parametro: "pass",
+			Mensaje:   "Solo se puede cifrar una partición primaria o lógica al crearla",
+		})
+	}
+
+	// Verificar parámetros obligatorios (size no aplica al eliminar ni al redimensionar)
+	if !hasSize && params.Delete == "" && !hasAdd {
 		errores = append(errores, FdiskError{
 			Parametro: "size",
 			Mensaje:   "El parámetro size es obligatorio",
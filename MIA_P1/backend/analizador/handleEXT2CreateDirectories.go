@@ -53,6 +53,12 @@ func HandleEXT2CreateDirectories(c *gin.Context) {
 		return
 	}
 
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	normalizedPath := normalizePath(req.Path)
 
 	// Si es una sobreescritura, manejarla directamente
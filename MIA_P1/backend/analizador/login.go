@@ -53,6 +53,12 @@ func HandleLogin(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearParticion(c, params.ID, DiskManager.DISK_LOCK_SHARED)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	// Leer el archivo users.txt para verificar credenciales
 	content, err := DiskManager.EXT2FileOperation(params.ID, "/users.txt", DiskManager.FILE_READ, "")
 	isValid, isAdmin, userGroup := validateCredentials(content, params.User, params.Pass)
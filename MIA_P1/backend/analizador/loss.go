@@ -0,0 +1,68 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// LossParams contiene los parámetros para los comandos LOSS y RECOVERY
+type LossParams struct {
+	Id string
+}
+
+// ValidarLoss extrae y valida el id de los comandos LOSS y RECOVERY
+func ValidarLoss(comando string) (LossParams, []Error) {
+	var params LossParams
+	var errores []Error
+
+	idRegex := regexp.MustCompile(`(?i)-id=([^\s]+)`)
+
+	// Extraer ID (obligatorio)
+	if matches := idRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.Id = strings.Trim(matches[1], "\"")
+	} else {
+		errores = append(errores, Error{
+			Parametro: "id",
+			Mensaje:   "El parámetro id es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleLoss procesa el comando LOSS, que simula la pérdida del sistema de archivos de una
+// partición EXT3 dejando solo el superbloque y el journal
+func HandleLoss(c *gin.Context, comando string) {
+	params, errores := ValidarLoss(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearParticion(c, params.Id, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	borrados, err := DiskManager.SimulateLoss(params.Id)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al simular la pérdida: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje": fmt.Sprintf("Pérdida simulada en la partición %s: se borraron %d bytes de bitmaps, inodos y bloques\nUse recovery -id=%s para recuperarla desde el journal",
+			params.Id, borrados, params.Id),
+		"bytes":      borrados,
+		"parametros": params,
+		"exito":      true,
+	})
+}
@@ -25,6 +25,12 @@ func HandleMkdir(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	// Normalizar la ruta y obtener directorio padre
 	dirPath := normalizePath(params.Path)
 	parentDir := filepath.Dir(dirPath)
@@ -16,8 +16,9 @@ type Error struct {
 
 func AnalizarMkdisk(comando string) (utils.DiskConfig, []Error, bool, string) {
 	params := utils.DiskConfig{
-		Fit:  "FF",
-		Unit: "M",
+		Fit:   "FF",
+		Unit:  "M",
+		Alloc: "zero",
 	}
 	var errores []Error
 
@@ -37,10 +38,11 @@ func AnalizarMkdisk(comando string) (utils.DiskConfig, []Error, bool, string) {
 
 	// Lista de parámetros válidos para mkdisk
 	validParams := map[string]bool{
-		"size": true,
-		"fit":  true,
-		"unit": true,
-		"path": true,
+		"size":  true,
+		"fit":   true,
+		"unit":  true,
+		"path":  true,
+		"alloc": true,
 	}
 
 	for _, match := range matches {
@@ -96,6 +98,17 @@ func AnalizarMkdisk(comando string) (utils.DiskConfig, []Error, bool, string) {
 				params.Unit = valueUnit
 			}
 
+		case "alloc":
+			valueAlloc := strings.ToLower(value)
+			if valueAlloc != "sparse" && valueAlloc != "zero" && valueAlloc != "full" {
+				errores = append(errores, Error{
+					Parametro: "alloc",
+					Mensaje:   "El valor de alloc debe ser sparse, zero o full",
+				})
+			} else {
+				params.Alloc = valueAlloc
+			}
+
 		case "path":
 			if value == "" {
 				continue
@@ -22,6 +22,12 @@ func HandleMkdisk(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	if err := DiskManager.CreateDisk(params); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"mensaje": fmt.Sprintf("Error al crear el disco: %s", err),
@@ -37,8 +43,8 @@ func HandleMkdisk(c *gin.Context, comando string) {
 // mostrarExitoMkdisk muestra mensaje de éxito de creación del disco junto a su información
 func mostrarExitoMkdisk(c *gin.Context, params utils.DiskConfig) {
 	sizeStr := fmt.Sprintf("%d%s", params.Size, params.Unit)
-	mensaje := fmt.Sprintf("Disco creado exitosamente:\nNombre: %s\nTamaño: %s\nRuta: %s",
-		params.Name, sizeStr, params.Path)
+	mensaje := fmt.Sprintf("Disco creado exitosamente:\nNombre: %s\nTamaño: %s\nRuta: %s\nReserva: %s",
+		params.Name, sizeStr, params.Path, params.Alloc)
 
 	c.JSON(http.StatusOK, gin.H{
 		"mensaje":    mensaje,
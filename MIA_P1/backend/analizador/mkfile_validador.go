@@ -38,6 +38,12 @@ func HandleMkfile(c *gin.Context, comando string) {
 				return
 			}
 
+			liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+			if !ok {
+				return
+			}
+			defer liberar()
+
 			// Preparar el contenido
 			var content string
 			if params.Cont != "" {
@@ -93,6 +99,12 @@ func HandleMkfile(c *gin.Context, comando string) {
 		return
 	}
 
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
 	// Normalizar la ruta
 	filePath := normalizePath(params.Path)
 
@@ -393,17 +405,12 @@ func getFileInode(id string, path string) (int, *DiskManager.Inode, error) {
 		return -1, nil, fmt.Errorf("partición no encontrada: %v", err)
 	}
 
-	file, err := os.OpenFile(mountedPartition.DiskPath, os.O_RDWR, 0666)
+	file, startByte, _, err := DiskManager.OpenMountedPartition(mountedPartition, os.O_RDWR)
 	if err != nil {
 		return -1, nil, fmt.Errorf("error al abrir disco: %v", err)
 	}
 	defer file.Close()
 
-	startByte, _, err := DiskManager.GetPartitionDetails(file, mountedPartition)
-	if err != nil {
-		return -1, nil, fmt.Errorf("error obteniendo detalles de partición: %v", err)
-	}
-
 	_, err = file.Seek(startByte, 0)
 	if err != nil {
 		return -1, nil, fmt.Errorf("error al posicionarse: %v", err)
@@ -10,8 +10,11 @@ import (
 
 // MkfsParams contiene los parámetros para el comando MKFS
 type MkfsParams struct {
-	Id   string
-	Type string
+	Id        string
+	Type      string
+	Fs        int32 // Tipo de sistema de archivos: EXT2 (2fs) o EXT3 (3fs)
+	BadBlocks []int32
+	Pass      string // Contraseña para cifrar la partición (opcional)
 }
 
 // HandleMkfs procesa el comando MKFS
@@ -22,6 +25,9 @@ func HandleMkfs(c *gin.Context, comando string) {
 	// Expresiones regulares para extraer parámetros
 	idRegex := regexp.MustCompile(`(?i)-id=([^\s]+)`)
 	typeRegex := regexp.MustCompile(`(?i)-type=([^\s]+)`)
+	fsRegex := regexp.MustCompile(`(?i)-fs=([^\s]+)`)
+	badBlocksRegex := regexp.MustCompile(`(?i)-badblocks=("[^"]+"|[^\s]+)`)
+	passRegex := regexp.MustCompile(`(?i)-pass=("[^"]+"|[^\s]+)`)
 
 	// Extraer ID (obligatorio)
 	idMatches := idRegex.FindStringSubmatch(comando)
@@ -53,13 +59,57 @@ func HandleMkfs(c *gin.Context, comando string) {
 		params.Type = "full"
 	}
 
+	// Extraer Fs (opcional, default: "2fs")
+	params.Fs = DiskManager.EXT2_FILESYSTEM_TYPE
+	if matches := fsRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		switch strings.ToLower(strings.Trim(matches[1], "\"")) {
+		case "2fs":
+		case "3fs":
+			params.Fs = DiskManager.EXT3_FILESYSTEM_TYPE
+		default:
+			errores = append(errores, Error{
+				Parametro: "fs",
+				Mensaje:   "El sistema de archivos debe ser '2fs' o '3fs'",
+			})
+		}
+	}
+
+	// Extraer BadBlocks (opcional): bloques a marcar como dañados, separados por coma
+	if matches := badBlocksRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		badBlocks, err := parseBadBlockList(matches[1])
+		if err != nil {
+			errores = append(errores, Error{
+				Parametro: "badblocks",
+				Mensaje:   err.Error(),
+			})
+		}
+		params.BadBlocks = badBlocks
+	}
+
+	// Extraer Pass (opcional): si se indica, la partición se cifra antes de formatearla
+	if matches := passRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.Pass = strings.Trim(matches[1], "\"")
+		if params.Pass == "" {
+			errores = append(errores, Error{
+				Parametro: "pass",
+				Mensaje:   "La contraseña no puede estar vacía",
+			})
+		}
+	}
+
 	// Si hay errores, mostrarlos
 	if len(errores) > 0 {
 		mostrarErrores(c, errores)
 		return
 	}
 
-	success, mensaje := DiskManager.FormatearParticion(params.Id, params.Type)
+	liberar, ok := bloquearParticion(c, params.Id, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	success, mensaje := DiskManager.FormatearParticion(params.Id, params.Type, params.Fs, params.BadBlocks, params.Pass)
 
 	if success {
 		c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,124 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"MIA_P1/backend/common"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// MkvolumeParams contiene los parámetros para el comando MKVOLUME
+type MkvolumeParams struct {
+	Name  string
+	Parts []string
+}
+
+// ValidarMkvolume extrae y valida los parámetros del comando MKVOLUME
+func ValidarMkvolume(comando string) (MkvolumeParams, []Error) {
+	var params MkvolumeParams
+	var errores []Error
+
+	nameRegex := regexp.MustCompile(`(?i)-name=("[^"]+"|[^\s]+)`)
+	partsRegex := regexp.MustCompile(`(?i)-parts=("[^"]+"|[^\s]+)`)
+
+	// Extraer Name (obligatorio)
+	if matches := nameRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.Name = strings.Trim(matches[1], "\"")
+		if len(params.Name) > 16 {
+			errores = append(errores, Error{
+				Parametro: "name",
+				Mensaje:   "El nombre del volumen no puede tener más de 16 caracteres",
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "name",
+			Mensaje:   "El parámetro name es obligatorio",
+		})
+	}
+
+	// Extraer Parts (obligatorio): IDs de particiones montadas separados por coma
+	if matches := partsRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		for _, id := range strings.Split(strings.Trim(matches[1], "\""), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				params.Parts = append(params.Parts, id)
+			}
+		}
+		if len(params.Parts) < 2 {
+			errores = append(errores, Error{
+				Parametro: "parts",
+				Mensaje:   "Un volumen necesita al menos dos particiones",
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "parts",
+			Mensaje:   "El parámetro parts es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleMkvolume procesa el comando MKVOLUME
+func HandleMkvolume(c *gin.Context, comando string) {
+	params, errores := ValidarMkvolume(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	// Se bloquean los discos de todas las partes; los IDs que no existan los rechaza CreateVolume
+	var discos []string
+	for _, id := range params.Parts {
+		if mp, err := DiskManager.FindMountedPartitionById(id); err == nil {
+			discos = append(discos, mp.DiskPath)
+		}
+	}
+	liberar, err := DiskManager.LockDisks(discos, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+	defer liberar()
+
+	volumen, err := DiskManager.CreateVolume(params.Name, params.Parts)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al crear el volumen: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	mensaje := fmt.Sprintf("Volumen '%s' creado y montado exitosamente:\nID: %s\nPartes:", volumen.PartitionName, volumen.ID)
+	for i, part := range volumen.VolumeParts {
+		mensaje += fmt.Sprintf("\n  %d. %s (%s)", i+1, part.PartitionName, part.DiskPath)
+	}
+
+	// Las particiones pasan a ser parte del volumen, así que una sesión en ellas se cierra
+	if CurrentSession != nil {
+		for _, id := range params.Parts {
+			if CurrentSession.PartitionID == id {
+				mensaje += fmt.Sprintf("\nSe cerró la sesión de %s en la partición %s.", CurrentSession.Username, id)
+				common.SetActiveUser(0, 0)
+				CurrentSession = nil
+				break
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":     mensaje,
+		"id":          volumen.ID,
+		"partes":      volumen.VolumeParts,
+		"particiones": DiskManager.GetMountedPartitions(),
+		"exito":       true,
+	})
+}
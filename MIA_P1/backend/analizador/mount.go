@@ -13,8 +13,11 @@ type MountError struct {
 }
 
 type MountParams struct {
-	Path string
-	Name string
+	Path     string
+	Name     string
+	ReadOnly bool   // -ro: montar en modo solo lectura
+	Mirror   string // -mirror: disco con la copia espejo de la partición (opcional)
+	Pass     string // -pass: contraseña de una partición cifrada (opcional)
 }
 
 func AnalizarMount(comando string) (MountParams, []MountError, bool) {
@@ -81,6 +84,46 @@ func AnalizarMount(comando string) (MountParams, []MountError, bool) {
 			params.Path = value
 			hasPath = true
 
+		case "mirror":
+			if strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+				value = value[1 : len(value)-1]
+			} else if strings.Contains(value, " ") {
+				errores = append(errores, MountError{
+					Parametro: "mirror",
+					Mensaje:   "Rutas con espacios deben estar entre comillas dobles",
+				})
+				continue
+			}
+
+			if !strings.HasSuffix(strings.ToLower(value), ".mia") {
+				errores = append(errores, MountError{
+					Parametro: "mirror",
+					Mensaje:   "El disco espejo debe tener extensión .mia",
+				})
+				continue
+			}
+
+			if !utils.DiskExists(value) {
+				errores = append(errores, MountError{
+					Parametro: "mirror",
+					Mensaje:   fmt.Sprintf("Error: No existe un disco en la ruta: %s", value),
+				})
+				continue
+			}
+
+			params.Mirror = value
+
+		case "pass":
+			value = strings.Trim(value, "\"")
+			if value == "" {
+				errores = append(errores, MountError{
+					Parametro: "pass",
+					Mensaje:   "La contraseña no puede estar vacía",
+				})
+				continue
+			}
+			params.Pass = value
+
 		case "name":
 			if value == "" {
 				errores = append(errores, MountError{
@@ -94,6 +137,9 @@ func AnalizarMount(comando string) (MountParams, []MountError, bool) {
 		}
 	}
 
+	// Bandera -ro (opcional)
+	params.ReadOnly = regexp.MustCompile(`(?i)(^|\s)-ro(\s|$)`).MatchString(comando)
+
 	// Verificar parámetros obligatorios
 	if !hasPath {
 		errores = append(errores, MountError{
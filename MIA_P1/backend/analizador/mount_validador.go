@@ -29,7 +29,42 @@ func handleMount(c *gin.Context, comando string) {
 	fmt.Printf("Debug: Intentando montar partición '%s' en disco '%s'\n", params.Name, params.Path)
 
 	// Montar la partición
-	id, err := DiskManager.MountPartition(params.Path, params.Name)
+	var liberar func()
+	var ok bool
+	if params.Mirror != "" {
+		liberar, ok = bloquearEspejo(c, params.Path, params.Mirror)
+	} else {
+		liberar, ok = bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_EXCLUSIVE)
+	}
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	// Una partición cifrada se desbloquea con la contraseña antes de montarla
+	if params.Pass != "" {
+		if err := DiskManager.UnlockPartition(params.Path, params.Name, params.Pass); err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": fmt.Sprintf("Error al montar la partición: %s", err),
+				"exito":   false,
+			})
+			return
+		}
+	}
+
+	var id, advertencia, estadoEspejo string
+	var err error
+	if params.Mirror != "" {
+		id, advertencia, estadoEspejo, err = DiskManager.MountMirroredPartition(params.Path, params.Mirror, params.Name, params.ReadOnly)
+	} else {
+		id, advertencia, err = DiskManager.MountPartition(params.Path, params.Name, params.ReadOnly)
+	}
+	// Si la partición no quedó montada tampoco queda desbloqueada
+	if err != nil && params.Pass != "" {
+		if _, errMontada := DiskManager.FindMountedPartitionByName(params.Path, params.Name); errMontada != nil {
+			DiskManager.ForgetPartitionKey(params.Path, params.Name)
+		}
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"mensaje": fmt.Sprintf("Error al montar la partición: %s", err),
@@ -43,13 +78,29 @@ func handleMount(c *gin.Context, comando string) {
 
 	// Preparar la respuesta
 	mensaje := fmt.Sprintf("Partición montada exitosamente:\nID: %s\nPath: %s\nNombre: %s", id, params.Path, params.Name)
+	if params.ReadOnly {
+		mensaje += "\nModo: solo lectura"
+	}
+	if params.Pass != "" {
+		mensaje += "\nCifrado: desbloqueada con contraseña"
+	}
+	if params.Mirror != "" {
+		mensaje += fmt.Sprintf("\nEspejo: %s (%s)", params.Mirror, estadoEspejo)
+	}
+	if advertencia != "" {
+		mensaje += fmt.Sprintf("\nAdvertencia: %s", advertencia)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"mensaje":     mensaje,
-		"id":          id,
-		"path":        params.Path,
-		"name":        params.Name,
-		"particiones": mountedPartitions,
-		"exito":       true,
+		"mensaje":      mensaje,
+		"id":           id,
+		"path":         params.Path,
+		"name":         params.Name,
+		"soloLectura":  params.ReadOnly,
+		"mirror":       params.Mirror,
+		"mirrorStatus": estadoEspejo,
+		"advertencia":  advertencia,
+		"particiones":  mountedPartitions,
+		"exito":        true,
 	})
 }
@@ -2,13 +2,16 @@ package analizador
 
 import (
 	"MIA_P1/backend/DiskManager"
+	"MIA_P1/backend/utils"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"regexp"
 	"strings"
 )
 
-// HandleMounted maneja el comando mounted para mostrar particiones montadas
+// HandleMounted maneja el comando mounted para mostrar particiones montadas.
+// Con -path solo muestra las del disco y con -path y -name busca esa partición.
 func HandleMounted(c *gin.Context, comando string) {
 	// Verificar que el comando comience con "mounted"
 	if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(comando)), "mounted") {
@@ -19,12 +22,51 @@ func HandleMounted(c *gin.Context, comando string) {
 		return
 	}
 
+	pathRegex := regexp.MustCompile(`(?i)-path=("[^"]+"|[^\s]+)`)
+	nameRegex := regexp.MustCompile(`(?i)-name=("[^"]+"|[^\s]+)`)
+
+	var diskPath, name string
+	if matches := pathRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		diskPath = strings.Trim(matches[1], "\"")
+	}
+	if matches := nameRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		name = strings.Trim(matches[1], "\"")
+	}
+	if name != "" && diskPath == "" {
+		mostrarErrores(c, []Error{{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio para buscar una partición por nombre",
+		}})
+		return
+	}
+
 	// Obtener particiones montadas
-	mountedPartitions := DiskManager.GetMountedPartitions()
+	var mountedPartitions []utils.MountedPartition
+	if name != "" {
+		mp, err := DiskManager.FindMountedPartitionByName(diskPath, name)
+		if err != nil {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": fmt.Sprintf("Error: %s", err),
+				"exito":   false,
+			})
+			return
+		}
+		mountedPartitions = append(mountedPartitions, *mp)
+	} else {
+		for _, mp := range DiskManager.GetMountedPartitions() {
+			if diskPath == "" || mp.UsesDisk(diskPath) {
+				mountedPartitions = append(mountedPartitions, mp)
+			}
+		}
+	}
 
 	if len(mountedPartitions) == 0 {
+		mensaje := "No hay particiones montadas actualmente"
+		if diskPath != "" {
+			mensaje = fmt.Sprintf("No hay particiones montadas del disco %s", diskPath)
+		}
 		c.JSON(http.StatusOK, gin.H{
-			"mensaje":     "No hay particiones montadas actualmente",
+			"mensaje":     mensaje,
 			"exito":       true,
 			"particiones": []string{},
 		})
@@ -41,8 +83,17 @@ func HandleMounted(c *gin.Context, comando string) {
 		mensaje += fmt.Sprintf("   Nombre: %s\n", mp.PartitionName)
 		mensaje += fmt.Sprintf("   Disco: %s\n", mp.DiskPath)
 		mensaje += fmt.Sprintf("   Tipo: %c\n", mp.PartitionType)
-		mensaje += fmt.Sprintf("   Letra: %c\n", mp.Letter)
+		mensaje += fmt.Sprintf("   Letra: %s\n", mp.Letter)
 		mensaje += fmt.Sprintf("   Número: %d\n", mp.Number)
+		if mp.ReadOnly {
+			mensaje += "   Modo: solo lectura\n"
+		}
+		if mp.MirrorPath != "" {
+			mensaje += fmt.Sprintf("   Espejo: %s (%s)\n", mp.MirrorPath, mp.MirrorStatus)
+		}
+		for j, part := range mp.VolumeParts {
+			mensaje += fmt.Sprintf("   Parte %d: %s (%s)\n", j+1, part.PartitionName, part.DiskPath)
+		}
 		mensaje += "\n"
 	}
 
@@ -50,12 +101,16 @@ func HandleMounted(c *gin.Context, comando string) {
 	partitionsData := make([]map[string]interface{}, 0, len(mountedPartitions))
 	for _, mp := range mountedPartitions {
 		partitionsData = append(partitionsData, map[string]interface{}{
-			"id":     mp.ID,
-			"path":   mp.DiskPath,
-			"name":   mp.PartitionName,
-			"type":   string(mp.PartitionType),
-			"letter": string(mp.Letter),
-			"number": mp.Number,
+			"id":           mp.ID,
+			"path":         mp.DiskPath,
+			"name":         mp.PartitionName,
+			"type":         string(mp.PartitionType),
+			"letter":       mp.Letter,
+			"number":       mp.Number,
+			"readOnly":     mp.ReadOnly,
+			"mirror":       mp.MirrorPath,
+			"mirrorStatus": mp.MirrorStatus,
+			"volumeParts":  mp.VolumeParts,
 		})
 	}
 
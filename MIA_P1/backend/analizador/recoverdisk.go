@@ -0,0 +1,140 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RecoverdiskParams contiene los parámetros para el comando RECOVERDISK
+type RecoverdiskParams struct {
+	Path string
+}
+
+// ValidarRecoverdisk extrae y valida los parámetros del comando RECOVERDISK
+func ValidarRecoverdisk(comando string) (RecoverdiskParams, []Error) {
+	var params RecoverdiskParams
+	var errores []Error
+
+	pathRegex := regexp.MustCompile(`(?i)-path=("[^"]+"|[^\s]+)`)
+
+	// Extraer Path (obligatorio)
+	pathMatches := pathRegex.FindStringSubmatch(comando)
+	if len(pathMatches) > 1 {
+		params.Path = strings.Trim(pathMatches[1], "\"")
+		if !strings.HasSuffix(strings.ToLower(params.Path), ".mia") {
+			errores = append(errores, Error{
+				Parametro: "path",
+				Mensaje:   "El archivo debe tener extensión .mia",
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleRecoverdisk muestra la tabla de particiones reconstruida (sin escribirla)
+// y pide confirmación antes de reemplazar el MBR
+func HandleRecoverdisk(c *gin.Context, comando string) {
+	params, errores := ValidarRecoverdisk(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_SHARED)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	plan, err := DiskManager.PlanDiskRecovery(params.Path)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al analizar el disco: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	if len(plan.Partitions) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje":   fmt.Sprintf("No se encontraron superbloques ni EBRs en %s, no hay nada que recuperar", params.Path),
+			"resultado": plan,
+			"exito":     false,
+		})
+		return
+	}
+
+	mensaje := "Simulación de recuperación (el disco no ha sido modificado):\n" +
+		formatRecoveryPlan(plan) +
+		"¿Desea escribir esta tabla de particiones en el MBR?"
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":              mensaje,
+		"resultado":            plan,
+		"exito":                true,
+		"requiereConfirmacion": true,
+		"tipoConfirmacion":     "recuperarDisco",
+		"comando":              comando,
+		"path":                 params.Path,
+	})
+}
+
+// confirmarRecoverdisk escribe el MBR reconstruido después de la confirmación del usuario
+func confirmarRecoverdisk(c *gin.Context, confirmReq ConfirmationRequest) {
+	params, errores := ValidarRecoverdisk(confirmReq.Comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	plan, err := DiskManager.RecoverDisk(params.Path)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al recuperar el disco: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":   "MBR reconstruido exitosamente:\n" + formatRecoveryPlan(plan),
+		"resultado": plan,
+		"exito":     true,
+	})
+}
+
+// formatRecoveryPlan arma el reporte de texto de la propuesta de recuperación
+func formatRecoveryPlan(plan *DiskManager.DiskRecoveryPlan) string {
+	reporte := fmt.Sprintf("Disco: %s (%d bytes)\n", plan.Path, plan.DiskSize)
+	for _, p := range plan.Partitions {
+		linea := fmt.Sprintf("  [%s] %s: inicio %d, tamaño %d bytes (encontrada por %s)",
+			p.Type, p.Name, p.Start, p.Size, p.Source)
+		if p.Estimated {
+			linea += ", tamaño estimado"
+		}
+		reporte += linea + "\n"
+	}
+	if len(plan.Discarded) > 0 {
+		reporte += "Descartados:\n"
+		for _, d := range plan.Discarded {
+			reporte += "  - " + d + "\n"
+		}
+	}
+	return reporte
+}
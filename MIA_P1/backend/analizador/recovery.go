@@ -0,0 +1,47 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// HandleRecovery procesa el comando RECOVERY, que reconstruye una partición EXT3 volviendo
+// a aplicar las operaciones de su journal
+func HandleRecovery(c *gin.Context, comando string) {
+	params, errores := ValidarLoss(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearParticion(c, params.Id, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	aplicadas, fallidas, err := DiskManager.RecoverFromJournal(params.Id)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al recuperar la partición: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	mensaje := fmt.Sprintf("Partición %s recuperada desde el journal\nOperaciones aplicadas: %d", params.Id, aplicadas)
+	if len(fallidas) > 0 {
+		mensaje += fmt.Sprintf("\nOperaciones que no se pudieron aplicar (%d):\n- %s", len(fallidas), strings.Join(fallidas, "\n- "))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    mensaje,
+		"aplicadas":  aplicadas,
+		"fallidas":   fallidas,
+		"parametros": params,
+		"exito":      true,
+	})
+}
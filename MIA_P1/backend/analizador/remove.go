@@ -0,0 +1,111 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// RemoveParams contiene los parámetros para el comando REMOVE
+type RemoveParams struct {
+	Path string
+}
+
+// ValidarRemove valida los parámetros del comando REMOVE
+func ValidarRemove(comando string) (RemoveParams, []Error) {
+	var params RemoveParams
+	var errores []Error
+
+	tokens := tokenizarComando(comando)
+
+	// Ignorar el primer token (remove)
+	for i := 1; i < len(tokens); i++ {
+		token := strings.TrimSpace(tokens[i])
+		if token == "" {
+			continue
+		}
+
+		parts := strings.SplitN(token, "=", 2)
+		paramName := strings.ToLower(strings.TrimPrefix(parts[0], "-"))
+		if !strings.HasPrefix(token, "-") || len(parts) < 2 {
+			errores = append(errores, Error{
+				Parametro: paramName,
+				Mensaje:   "Parámetro no reconocido para remove",
+			})
+			continue
+		}
+
+		switch paramName {
+		case "path":
+			params.Path = strings.Trim(parts[1], "\"")
+		default:
+			errores = append(errores, Error{
+				Parametro: paramName,
+				Mensaje:   "Parámetro no reconocido para remove",
+			})
+		}
+	}
+
+	if params.Path == "" {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleRemove procesa el comando REMOVE, que elimina un archivo o una carpeta con su
+// contenido. Lo que el usuario no puede modificar se conserva y se informa.
+func HandleRemove(c *gin.Context, comando string) {
+	if CurrentSession == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": "Error: No hay una sesión activa. Debe iniciar sesión primero.",
+			"exito":   false,
+		})
+		return
+	}
+
+	params, errores := ValidarRemove(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	path := normalizePath(params.Path)
+	conservados, err := DiskManager.RemoveEXT2Path(CurrentSession.PartitionID, path,
+		CurrentSession.Username, CurrentSession.UserGroup)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al eliminar '%s': %s", path, err),
+			"exito":   false,
+		})
+		return
+	}
+
+	if len(conservados) > 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("'%s' se eliminó parcialmente: no tiene permiso de escritura sobre %d ruta(s), que se conservaron con su contenido:\n%s",
+				path, len(conservados), strings.Join(conservados, "\n")),
+			"conservados": conservados,
+			"parametros":  params,
+			"exito":       false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    fmt.Sprintf("'%s' eliminado exitosamente", path),
+		"parametros": params,
+		"exito":      true,
+	})
+}
@@ -0,0 +1,120 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// RenameParams contiene los parámetros para el comando RENAME
+type RenameParams struct {
+	Path string
+	Name string
+}
+
+// ValidarRename valida los parámetros del comando RENAME
+func ValidarRename(comando string) (RenameParams, []Error) {
+	var params RenameParams
+	var errores []Error
+
+	tokens := tokenizarComando(comando)
+
+	// Ignorar el primer token (rename)
+	for i := 1; i < len(tokens); i++ {
+		token := strings.TrimSpace(tokens[i])
+		if token == "" {
+			continue
+		}
+
+		parts := strings.SplitN(token, "=", 2)
+		paramName := strings.ToLower(strings.TrimPrefix(parts[0], "-"))
+		if !strings.HasPrefix(token, "-") || len(parts) < 2 {
+			errores = append(errores, Error{
+				Parametro: paramName,
+				Mensaje:   "Parámetro no reconocido para rename",
+			})
+			continue
+		}
+		paramValue := strings.Trim(parts[1], "\"")
+
+		switch paramName {
+		case "path":
+			params.Path = paramValue
+		case "name":
+			params.Name = paramValue
+			if len(paramValue) > DiskManager.B_NAME_SIZE {
+				errores = append(errores, Error{
+					Parametro: "name",
+					Mensaje:   fmt.Sprintf("El nombre no puede tener más de %d caracteres", DiskManager.B_NAME_SIZE),
+				})
+			} else if strings.Contains(paramValue, "/") {
+				errores = append(errores, Error{
+					Parametro: "name",
+					Mensaje:   "El nombre no puede contener '/'",
+				})
+			}
+		default:
+			errores = append(errores, Error{
+				Parametro: paramName,
+				Mensaje:   "Parámetro no reconocido para rename",
+			})
+		}
+	}
+
+	if params.Path == "" {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+	if params.Name == "" {
+		errores = append(errores, Error{
+			Parametro: "name",
+			Mensaje:   "El parámetro name es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleRename procesa el comando RENAME, que cambia el nombre de un archivo o carpeta
+func HandleRename(c *gin.Context, comando string) {
+	if CurrentSession == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": "Error: No hay una sesión activa. Debe iniciar sesión primero.",
+			"exito":   false,
+		})
+		return
+	}
+
+	params, errores := ValidarRename(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearParticion(c, CurrentSession.PartitionID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	path := normalizePath(params.Path)
+	err := DiskManager.RenameEXT2Path(CurrentSession.PartitionID, path, params.Name,
+		CurrentSession.Username, CurrentSession.UserGroup)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al renombrar '%s': %s", path, err),
+			"exito":   false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    fmt.Sprintf("'%s' renombrado a '%s'", path, params.Name),
+		"parametros": params,
+		"exito":      true,
+	})
+}
@@ -0,0 +1,118 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ResizediskParams contiene los parámetros para el comando RESIZEDISK
+type ResizediskParams struct {
+	Path string
+	Size int
+	Unit string
+}
+
+// ValidarResizedisk extrae y valida los parámetros del comando RESIZEDISK
+func ValidarResizedisk(comando string) (ResizediskParams, []Error) {
+	params := ResizediskParams{Unit: "M"}
+	var errores []Error
+
+	// Expresiones regulares para extraer parámetros
+	pathRegex := regexp.MustCompile(`(?i)-path=("[^"]+"|[^\s]+)`)
+	sizeRegex := regexp.MustCompile(`(?i)-size=([^\s]+)`)
+	unitRegex := regexp.MustCompile(`(?i)-unit=([^\s]+)`)
+
+	// Extraer Path (obligatorio)
+	pathMatches := pathRegex.FindStringSubmatch(comando)
+	if len(pathMatches) > 1 {
+		params.Path = strings.Trim(pathMatches[1], "\"")
+		if !strings.HasSuffix(strings.ToLower(params.Path), ".mia") {
+			errores = append(errores, Error{
+				Parametro: "path",
+				Mensaje:   "El archivo debe tener extensión .mia",
+			})
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+
+	// Extraer Size (obligatorio)
+	sizeMatches := sizeRegex.FindStringSubmatch(comando)
+	if len(sizeMatches) > 1 {
+		size, err := strconv.Atoi(strings.Trim(sizeMatches[1], "\""))
+		if err != nil || size <= 0 {
+			errores = append(errores, Error{
+				Parametro: "size",
+				Mensaje:   "El tamaño debe ser un número positivo mayor que cero",
+			})
+		} else {
+			params.Size = size
+		}
+	} else {
+		errores = append(errores, Error{
+			Parametro: "size",
+			Mensaje:   "El parámetro size es obligatorio",
+		})
+	}
+
+	// Extraer Unit (opcional, por defecto M)
+	unitMatches := unitRegex.FindStringSubmatch(comando)
+	if len(unitMatches) > 1 {
+		params.Unit = strings.ToUpper(strings.Trim(unitMatches[1], "\""))
+		if params.Unit != "K" && params.Unit != "M" {
+			errores = append(errores, Error{
+				Parametro: "unit",
+				Mensaje:   "El valor de unit debe ser K o M",
+			})
+		}
+	}
+
+	return params, errores
+}
+
+// HandleResizedisk procesa el comando RESIZEDISK
+func HandleResizedisk(c *gin.Context, comando string) {
+	params, errores := ValidarResizedisk(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearDisco(c, params.Path, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	oldSize, newSize, err := DiskManager.ResizeDisk(params.Path, params.Size, params.Unit)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al redimensionar el disco: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	accion := "ampliado"
+	if newSize < oldSize {
+		accion = "reducido"
+	}
+	mensaje := fmt.Sprintf("Disco %s exitosamente:\nRuta: %s\nTamaño anterior: %d bytes\nTamaño nuevo: %d bytes",
+		accion, params.Path, oldSize, newSize)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    mensaje,
+		"ruta":       params.Path,
+		"tamanio":    fmt.Sprintf("%d%s", params.Size, params.Unit),
+		"parametros": params,
+		"exito":      true,
+	})
+}
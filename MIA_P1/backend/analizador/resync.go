@@ -0,0 +1,70 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ResyncParams contiene los parámetros para el comando RESYNC
+type ResyncParams struct {
+	Id string
+}
+
+// ValidarResync extrae y valida los parámetros del comando RESYNC
+func ValidarResync(comando string) (ResyncParams, []Error) {
+	var params ResyncParams
+	var errores []Error
+
+	idRegex := regexp.MustCompile(`(?i)-id=([^\s]+)`)
+
+	// Extraer ID (obligatorio)
+	if matches := idRegex.FindStringSubmatch(comando); len(matches) > 1 {
+		params.Id = strings.Trim(matches[1], "\"")
+	} else {
+		errores = append(errores, Error{
+			Parametro: "id",
+			Mensaje:   "El parámetro id es obligatorio",
+		})
+	}
+
+	return params, errores
+}
+
+// HandleResync procesa el comando RESYNC, que reconstruye el espejo de una partición montada
+func HandleResync(c *gin.Context, comando string) {
+	params, errores := ValidarResync(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	liberar, ok := bloquearParticion(c, params.Id, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	direccion, copiados, err := DiskManager.ResyncMirror(params.Id)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al sincronizar el espejo: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	mensaje := fmt.Sprintf("Espejo de la partición %s sincronizado\nDirección: %s\nBytes reescritos: %d",
+		params.Id, direccion, copiados)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":    mensaje,
+		"direccion":  direccion,
+		"bytes":      copiados,
+		"parametros": params,
+		"exito":      true,
+	})
+}
@@ -0,0 +1,78 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ScandisksParams contiene los parámetros para el comando SCANDISKS
+type ScandisksParams struct {
+	Path      string
+	Recursive bool
+}
+
+// HandleScandisks procesa el comando SCANDISKS
+func HandleScandisks(c *gin.Context, comando string) {
+	var params ScandisksParams
+	var errores []Error
+
+	// Expresiones regulares para extraer parámetros
+	pathRegex := regexp.MustCompile(`(?i)-path=("[^"]+"|[^\s]+)`)
+	recursiveRegex := regexp.MustCompile(`(?i)(^|\s)-r(\s|$)`)
+
+	// Extraer Path (obligatorio)
+	pathMatches := pathRegex.FindStringSubmatch(comando)
+	if len(pathMatches) > 1 {
+		params.Path = strings.Trim(pathMatches[1], "\"")
+	} else {
+		errores = append(errores, Error{
+			Parametro: "path",
+			Mensaje:   "El parámetro path es obligatorio",
+		})
+	}
+
+	// Bandera -r (opcional): buscar también en subdirectorios
+	params.Recursive = recursiveRegex.MatchString(comando)
+
+	// Si hay errores, mostrarlos
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	results, err := DiskManager.ScanDisks(params.Path, params.Recursive)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al escanear discos: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	// Armar el resumen
+	registrados := 0
+	mensaje := fmt.Sprintf("Escaneo de %s:\n", params.Path)
+	for _, r := range results {
+		switch {
+		case r.Registered:
+			registrados++
+			mensaje += fmt.Sprintf("- %s: registrado\n", r.Path)
+		case r.Valid:
+			mensaje += fmt.Sprintf("- %s: %s\n", r.Path, r.Reason)
+		default:
+			mensaje += fmt.Sprintf("- %s: inválido (%s)\n", r.Path, r.Reason)
+		}
+	}
+	mensaje += fmt.Sprintf("Archivos .mia encontrados: %d, discos registrados: %d", len(results), registrados)
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":     mensaje,
+		"resultados":  results,
+		"registrados": registrados,
+		"exito":       true,
+	})
+}
@@ -0,0 +1,49 @@
+package analizador
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"MIA_P1/backend/common"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// HandleUnmount procesa el comando unmount
+func HandleUnmount(c *gin.Context, comando string) {
+	// Validar los parámetros del comando
+	params, errores := ValidarUnmount(comando)
+	if len(errores) > 0 {
+		mostrarErrores(c, errores)
+		return
+	}
+
+	// Desmontar la partición (actualiza MBR/EBR y superbloque)
+	liberar, ok := bloquearParticion(c, params.ID, DiskManager.DISK_LOCK_EXCLUSIVE)
+	if !ok {
+		return
+	}
+	defer liberar()
+
+	if err := DiskManager.UnmountPartition(params.ID); err != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"mensaje": fmt.Sprintf("Error al desmontar la partición: %s", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	// Si la sesión activa estaba en esta partición, se cierra
+	mensaje := fmt.Sprintf("Partición %s desmontada exitosamente.", params.ID)
+	if CurrentSession != nil && CurrentSession.PartitionID == params.ID {
+		mensaje += fmt.Sprintf("\nSe cerró la sesión de %s en la partición.", CurrentSession.Username)
+		common.SetActiveUser(0, 0)
+		CurrentSession = nil
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mensaje":     mensaje,
+		"id":          params.ID,
+		"particiones": DiskManager.GetMountedPartitions(),
+		"exito":       true,
+	})
+}
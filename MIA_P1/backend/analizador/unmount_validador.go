@@ -0,0 +1,77 @@
+package analizador
+
+import (
+	"strings"
+)
+
+// UnmountParams contiene los parámetros para el comando unmount
+type UnmountParams struct {
+	ID string
+}
+
+// ValidarUnmount valida los parámetros del comando unmount
+func ValidarUnmount(comando string) (*UnmountParams, []Error) {
+	var errores []Error
+	var id string
+
+	// Dividir el comando en tokens
+	tokens := strings.Split(comando, " ")
+
+	// Ignorar el primer token (unmount)
+	for i := 1; i < len(tokens); i++ {
+		token := strings.TrimSpace(tokens[i])
+
+		// Ignorar tokens vacíos
+		if token == "" {
+			continue
+		}
+
+		var paramName, paramValue string
+		if strings.HasPrefix(token, "-") && strings.Contains(token, "=") {
+			// Formato -param=valor
+			parts := strings.SplitN(token, "=", 2)
+			paramName = strings.ToLower(strings.TrimPrefix(parts[0], "-"))
+			paramValue = parts[1]
+		} else if strings.HasPrefix(token, "-") {
+			// Formato -param valor
+			paramName = strings.ToLower(strings.TrimPrefix(token, "-"))
+			if i+1 >= len(tokens) {
+				errores = append(errores, Error{
+					Parametro: paramName,
+					Mensaje:   "Falta valor para el parámetro",
+				})
+				continue
+			}
+			paramValue = strings.TrimSpace(tokens[i+1])
+			i++ // Avanzar para saltarse el valor
+		} else {
+			continue
+		}
+
+		switch paramName {
+		case "id":
+			id = strings.Trim(paramValue, "\"")
+		default:
+			errores = append(errores, Error{
+				Parametro: paramName,
+				Mensaje:   "Parámetro no reconocido para unmount",
+			})
+		}
+	}
+
+	// Validar parámetros obligatorios
+	if id == "" {
+		errores = append(errores, Error{
+			Parametro: "id",
+			Mensaje:   "El parámetro id es obligatorio",
+		})
+	}
+
+	if len(errores) > 0 {
+		return nil, errores
+	}
+
+	return &UnmountParams{
+		ID: id,
+	}, errores
+}
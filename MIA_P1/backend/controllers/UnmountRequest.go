@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"MIA_P1/backend/DiskManager"
+	"MIA_P1/backend/analizador"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// UnmountRequest estructura para recibir la partición a desmontar desde el frontend.
+// Se acepta el ID de montaje o la pareja path/name del disco y la partición.
+type UnmountRequest struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	Name string `json:"name"`
+}
+
+// UnmountPartition desmonta una partición mediante API REST
+func UnmountPartition(c *gin.Context) {
+	var unmountReq UnmountRequest
+
+	// Parsear el JSON del body
+	if err := c.ShouldBindJSON(&unmountReq); err != nil || (unmountReq.ID == "" && (unmountReq.Path == "" || unmountReq.Name == "")) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"mensaje": "Error en los datos proporcionados. Se requiere id, o path y name.",
+			"exito":   false,
+		})
+		return
+	}
+
+	// Buscar el ID a partir del disco y el nombre de la partición
+	if unmountReq.ID == "" {
+		for _, mp := range DiskManager.GetMountedPartitions() {
+			if mp.DiskPath == unmountReq.Path && mp.PartitionName == unmountReq.Name {
+				unmountReq.ID = mp.ID
+				break
+			}
+		}
+		if unmountReq.ID == "" {
+			c.JSON(http.StatusOK, gin.H{
+				"mensaje": fmt.Sprintf("La partición '%s' del disco %s no está montada", unmountReq.Name, unmountReq.Path),
+				"exito":   false,
+			})
+			return
+		}
+	}
+
+	// Convertir los parámetros al formato de comando esperado por el analizador
+	comando := fmt.Sprintf("unmount -id=%s", unmountReq.ID)
+
+	// Llamar a la función existente de unmount
+	analizador.HandleUnmount(c, comando)
+}
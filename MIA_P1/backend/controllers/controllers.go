@@ -21,6 +21,36 @@ func GetAllDisks(c *gin.Context) {
 	})
 }
 
+// ScanDisks busca imágenes .mia en un directorio del host y registra las válidas
+func ScanDisks(c *gin.Context) {
+	dir := c.Query("path")
+	if dir == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"mensaje": "Se requiere el parámetro 'path'",
+			"exito":   false,
+		})
+		return
+	}
+	recursive := c.Query("recursive") == "true" || c.Query("r") == "true"
+
+	results, err := DiskManager.ScanDisks(dir, recursive)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"mensaje": fmt.Sprintf("Error escaneando discos: %v", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	disks := DiskManager.GetAllDisks()
+	c.JSON(http.StatusOK, gin.H{
+		"resultados": results,
+		"discos":     disks,
+		"total":      len(disks),
+		"exito":      true,
+	})
+}
+
 // GetAllDiskAnalysis obtiene el análisis completo de todos los discos registrados
 func GetAllDiskAnalysis(c *gin.Context) {
 	analyses, err := DiskManager.AnalyzeAllDisks()
@@ -65,7 +95,43 @@ func GetDiskAnalysis(c *gin.Context) {
 	})
 }
 
-// GetAllPartitionsInfo obtiene información básica de todas las particiones
+// CheckDisk revisa la tabla de particiones de un disco sin modificarlo
+func CheckDisk(c *gin.Context) {
+	checkDisk(c, false)
+}
+
+// RepairDisk revisa la tabla de particiones de un disco y corrige lo que es seguro
+func RepairDisk(c *gin.Context) {
+	checkDisk(c, true)
+}
+
+func checkDisk(c *gin.Context, repair bool) {
+	path := c.Query("path")
+	if path == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"mensaje": "Se requiere el parámetro 'path'",
+			"exito":   false,
+		})
+		return
+	}
+
+	result, err := DiskManager.CheckDisk(path, repair)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"mensaje": fmt.Sprintf("Error revisando disco: %v", err),
+			"exito":   false,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resultado": result,
+		"exito":     true,
+	})
+}
+
+// GetAllPartitionsInfo obtiene información básica de todas las particiones, opcionalmente
+// filtradas por disco (?disk=) y nombre (?name=)
 func GetAllPartitionsInfo(c *gin.Context) {
 	partitions, err := DiskManager.GetAllPartitionsInfo()
 	if err != nil {
@@ -76,6 +142,33 @@ func GetAllPartitionsInfo(c *gin.Context) {
 		return
 	}
 
+	// Filtros opcionales: ?disk=ruta y ?name=particion
+	diskPath := c.Query("disk")
+	partName := c.Query("name")
+	if partName != "" && diskPath == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"mensaje": "Se requiere el parámetro 'disk' para buscar una partición por nombre",
+			"exito":   false,
+		})
+		return
+	}
+	if diskPath != "" {
+		filtered := []DiskManager.BasicPartitionInfo{}
+		for _, p := range partitions {
+			if p.DiskPath == diskPath && (partName == "" || p.Name == partName) {
+				filtered = append(filtered, p)
+			}
+		}
+		if partName != "" && len(filtered) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{
+				"mensaje": fmt.Sprintf("No se encontró la partición '%s' en el disco %s", partName, diskPath),
+				"exito":   false,
+			})
+			return
+		}
+		partitions = filtered
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"particiones": partitions,
 		"total":       len(partitions),
@@ -29,11 +29,15 @@ func SetupRouter() *gin.Engine {
 	r.POST("/ext2-crear-directorios", analizador.HandleEXT2CreateDirectories)
 	// Nueva ruta para obtener listado de discos fase 2
 	r.GET("/api/disks", controllers.GetAllDisks)
+	r.POST("/api/disks/scan", controllers.ScanDisks)
 	r.GET("/api/disks/analysis", controllers.GetAllDiskAnalysis)
 	r.GET("/api/disk/analysis", controllers.GetDiskAnalysis)
+	r.GET("/api/disk/check", controllers.CheckDisk)
+	r.POST("/api/disk/repair", controllers.RepairDisk)
 	r.GET("/api/partitions", controllers.GetAllPartitionsInfo)
 	r.GET("/api/disk/partitions", controllers.GetDiskPartitionsInfo)
 	r.GET("/api/partition", controllers.GetPartitionInfo)
+	r.POST("/api/partition/unmount", controllers.UnmountPartition)
 	r.GET("/api/filesystem", controllers.GetFileSystem) // Obtener toda la estructura
 	r.GET("/api/file", controllers.GetFileContent)      // Obtener contenido de un archivo específico
 	r.GET("/api/directory", controllers.ListDirectory)  // Listar contenido de un directorio
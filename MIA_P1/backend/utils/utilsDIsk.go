@@ -15,6 +15,7 @@ type DiskConfig struct {
 	Path      string
 	Name      string
 	Extension string
+	Alloc     string // sparse, zero o full
 }
 
 // ValidarRuta verifica si existe una ruta y si es un directorio válido
@@ -1,16 +1,23 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 )
 
 type PartitionConfig struct {
-	Size int    // Tamaño de la partición
-	Path string // Ruta del disco
-	Name string // Nombre de la partición
-	Type string // Tipo de partición (P, E, L)
-	Fit  string // Tipo de ajuste (BF, FF, WF)
-	Unit string // Unidad de medida (B, K, M)
+	Size   int    // Tamaño de la partición
+	Path   string // Ruta del disco
+	Name   string // Nombre de la partición
+	Type   string // Tipo de partición (P, E, L)
+	Fit    string // Tipo de ajuste (BF, FF, WF)
+	Unit   string // Unidad de medida (B, K, M)
+	Delete string // Modo de eliminación (fast, full), vacío si no se elimina
+	Add    int    // Espacio a agregar (positivo) o quitar (negativo), 0 si no se redimensiona
+	Pass   string `json:"-"` // Contraseña para cifrar la partición, vacía si no se cifra
 }
 
 func NewPartitionConfig() PartitionConfig {
@@ -21,54 +28,177 @@ func NewPartitionConfig() PartitionConfig {
 	}
 }
 
+// Configuración del formato de los IDs de montaje
+const (
+	// ID_PREFIX_ENV cambia el prefijo de los IDs (por defecto los dos últimos dígitos del carnet)
+	ID_PREFIX_ENV = "MIA_ID_PREFIX"
+	// ID_FORMAT_ENV cambia cómo se arma el ID a partir de {prefijo}, {numero} y {letra}
+	ID_FORMAT_ENV     = "MIA_ID_FORMAT"
+	DEFAULT_ID_PREFIX = "15"
+	DEFAULT_ID_FORMAT = "{prefijo}{numero}{letra}"
+)
+
 // Estructura para almacenar información de particiones montadas
 // MountedPartition representa una partición montada en el sistema
 type MountedPartition struct {
 	ID            string
 	DiskPath      string
 	PartitionName string
-	PartitionType byte // 'P' para primaria, 'L' para lógica
-	Status        byte // Estado de la partición (montada o no)
-	Letter        byte // Letra asignada (A, B, C, etc.)
-	Number        int  // Número de partición
+	PartitionType byte         // 'P' para primaria, 'L' para lógica, 'V' para volumen
+	Status        byte         // Estado de la partición (montada o no)
+	Letter        string       // Letra asignada al disco (A, B, ..., Z, AA, AB, ...)
+	Number        int          // Número de partición
+	ReadOnly      bool         // Montada con -ro: se rechaza cualquier escritura
+	MirrorPath    string       // Disco espejo (RAID-1) con una partición del mismo nombre, vacío si no tiene
+	MirrorStatus  string       // Estado del espejo: sincronizado, desactualizado o degradado
+	VolumeParts   []VolumePart // Particiones que forman un volumen de mkvolume, en orden
+}
+
+// VolumePart identifica una de las particiones que forman un volumen
+type VolumePart struct {
+	DiskPath      string
+	PartitionName string
+}
+
+// UsesDisk indica si la partición montada ocupa el disco, como principal, como espejo
+// o con alguna de las partes de un volumen
+func (mp *MountedPartition) UsesDisk(diskPath string) bool {
+	for _, part := range mp.VolumeParts {
+		if part.DiskPath == diskPath {
+			return true
+		}
+	}
+	return mp.DiskPath == diskPath || (mp.MirrorPath != "" && mp.MirrorPath == diskPath)
+}
+
+// UsesPartition indica si la partición name del disco es esta montada, su espejo o
+// una de las partes de un volumen
+func (mp *MountedPartition) UsesPartition(diskPath, name string) bool {
+	if len(mp.VolumeParts) > 0 {
+		for _, part := range mp.VolumeParts {
+			if part.DiskPath == diskPath && part.PartitionName == name {
+				return true
+			}
+		}
+		return false
+	}
+	return mp.PartitionName == name && mp.UsesDisk(diskPath)
+}
+
+// UnmarshalJSON acepta también la letra guardada como byte por versiones anteriores del estado
+func (mp *MountedPartition) UnmarshalJSON(data []byte) error {
+	type mountedPartitionJSON MountedPartition
+	aux := struct {
+		*mountedPartitionJSON
+		Letter json.RawMessage
+	}{mountedPartitionJSON: (*mountedPartitionJSON)(mp)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Letter) == 0 {
+		return nil
+	}
+
+	var oldLetter byte
+	if err := json.Unmarshal(aux.Letter, &oldLetter); err == nil {
+		mp.Letter = string(oldLetter)
+		return nil
+	}
+	return json.Unmarshal(aux.Letter, &mp.Letter)
 }
 
 // Variable global para almacenar las particiones montadas
 var MountedPartitions []MountedPartition
 
-// GenerateID genera un ID según el formato especificado
-func GenerateID(carnet string, number int, letter byte) string {
-	// Obtener los últimos dos dígitos del carnet
-	lastTwoDigits := "00"
-	if len(carnet) >= 2 {
-		lastTwoDigits = carnet[len(carnet)-2:]
+// GetIDPrefix retorna el prefijo configurado para los IDs de montaje
+func GetIDPrefix() string {
+	if prefix := strings.TrimSpace(os.Getenv(ID_PREFIX_ENV)); prefix != "" {
+		return prefix
 	}
+	return DEFAULT_ID_PREFIX
+}
+
+// GetIDFormat retorna el formato configurado para los IDs de montaje. Un formato sin
+// {numero} o sin {letra} no distingue particiones, así que se usa el de por defecto.
+func GetIDFormat() string {
+	format := strings.TrimSpace(os.Getenv(ID_FORMAT_ENV))
+	if format == "" {
+		return DEFAULT_ID_FORMAT
+	}
+	if !strings.Contains(format, "{numero}") || !strings.Contains(format, "{letra}") {
+		fmt.Printf("Advertencia: el formato de ID '%s' debe incluir {numero} y {letra}, se usa '%s'\n",
+			format, DEFAULT_ID_FORMAT)
+		return DEFAULT_ID_FORMAT
+	}
+	return format
+}
 
-	return fmt.Sprintf("%s%d%c", lastTwoDigits, number, letter)
+// GenerateID genera un ID con el prefijo y el formato configurados
+func GenerateID(number int, letter string) string {
+	return strings.NewReplacer(
+		"{prefijo}", GetIDPrefix(),
+		"{numero}", strconv.Itoa(number),
+		"{letra}", letter,
+	).Replace(GetIDFormat())
 }
 
-// GetNextPartitionNumber obtiene el siguiente número de partición para un disco específico
-func GetNextPartitionNumber(diskPath string, letter byte) int {
-	maxNumber := 0
+// GenerateUniqueID asigna letra y número a una partición del disco y arma su ID. Si el
+// formato configurado produce un ID que ya está en uso se prueba con el siguiente número,
+// así el ID nunca se repite entre discos.
+func GenerateUniqueID(diskPath string) (string, string, int) {
+	letter := GetNextLetter(diskPath)
+	number := GetNextPartitionNumber(diskPath, letter)
+
+	usedIDs := make(map[string]bool)
+	for _, mp := range MountedPartitions {
+		usedIDs[strings.ToUpper(mp.ID)] = true
+	}
+
+	id := GenerateID(number, letter)
+	for usedIDs[strings.ToUpper(id)] || numberInUse(diskPath, letter, number) {
+		number++
+		id = GenerateID(number, letter)
+	}
+	return id, letter, number
+}
 
-	// Buscar el número más alto para este disco y esta letra
+// numberInUse indica si el número ya está asignado a otra partición del disco
+func numberInUse(diskPath, letter string, number int) bool {
+	for _, mp := range MountedPartitions {
+		if mp.DiskPath == diskPath && mp.Letter == letter && mp.Number == number {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNextPartitionNumber obtiene el siguiente número de partición para un disco específico.
+// Se reutiliza el menor número libre para que los IDs de particiones desmontadas vuelvan a usarse.
+func GetNextPartitionNumber(diskPath string, letter string) int {
+	usedNumbers := make(map[int]bool)
+
+	// Buscar los números ya usados para este disco y esta letra
 	for _, mp := range MountedPartitions {
 		if mp.DiskPath == diskPath && mp.Letter == letter {
-			if mp.Number > maxNumber {
-				maxNumber = mp.Number
-			}
+			usedNumbers[mp.Number] = true
 		}
 	}
 
-	// Incrementar el número en 1
-	nextNumber := maxNumber + 1
-	fmt.Printf("Debug: Siguiente número para disco %s, letra %c: %d\n", diskPath, letter, nextNumber)
+	// Tomar el primer número libre
+	nextNumber := 1
+	for usedNumbers[nextNumber] {
+		nextNumber++
+	}
+	fmt.Printf("Debug: Siguiente número para disco %s, letra %s: %d\n", diskPath, letter, nextNumber)
 	return nextNumber
 }
 
-func GetNextLetter(diskPath string) byte {
+// GetNextLetter retorna la letra del disco, o la primera libre de la secuencia
+// A, B, ..., Z, AA, AB, ... si el disco todavía no tiene particiones montadas
+func GetNextLetter(diskPath string) string {
 	// Mapa para asociar discos con sus letras
-	diskLetters := make(map[string]byte)
+	diskLetters := make(map[string]string)
 
 	// Recorrer todas las particiones montadas para encontrar qué letras ya están asignadas a cada disco
 	for _, mp := range MountedPartitions {
@@ -77,24 +207,33 @@ func GetNextLetter(diskPath string) byte {
 
 	// Si el disco ya tiene una letra asignada, retornarla
 	if letter, exists := diskLetters[diskPath]; exists {
-		fmt.Printf("Debug: Disco %s ya tiene letra asignada: %c\n", diskPath, letter)
+		fmt.Printf("Debug: Disco %s ya tiene letra asignada: %s\n", diskPath, letter)
 		return letter
 	}
 
-	// Si el disco no tiene letra, asignar la siguiente disponible en el alfabeto
-	usedLetters := make(map[byte]bool)
+	// Si el disco no tiene letra, asignar la siguiente disponible
+	usedLetters := make(map[string]bool)
 	for _, letter := range diskLetters {
 		usedLetters[letter] = true
 	}
 
-	// Buscar la primera letra disponible
-	for letter := byte('A'); letter <= byte('Z'); letter++ {
+	// La secuencia no se acaba, así que siempre hay una letra libre
+	for i := 0; ; i++ {
+		letter := LetterForIndex(i)
 		if !usedLetters[letter] {
-			fmt.Printf("Debug: Asignando nueva letra %c a disco %s\n", letter, diskPath)
+			fmt.Printf("Debug: Asignando nueva letra %s a disco %s\n", letter, diskPath)
 			return letter
 		}
 	}
+}
 
-	// Si todas las letras están usadas, volver a A (no debería ocurrir en la práctica)
-	return 'A'
+// LetterForIndex convierte un índice en letras como las columnas de una hoja de cálculo:
+// 0 -> A, 25 -> Z, 26 -> AA, 27 -> AB...
+func LetterForIndex(index int) string {
+	letters := ""
+	for index >= 0 {
+		letters = string(rune('A'+index%26)) + letters
+		index = index/26 - 1
+	}
+	return letters
 }
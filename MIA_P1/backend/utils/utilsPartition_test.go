@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLetterForIndex(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{1, "B"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := LetterForIndex(tt.index); got != tt.want {
+			t.Errorf("LetterForIndex(%d) = %q, se esperaba %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateID(t *testing.T) {
+	tests := []struct {
+		name           string
+		prefix, format string
+		want           string
+	}{
+		{"por defecto", "", "", "153B"},
+		{"prefijo configurado", "99", "", "993B"},
+		{"formato configurado", "", "{letra}-{numero}-{prefijo}", "B-3-15"},
+		{"formato sin letra", "99", "{prefijo}{numero}", "993B"},
+		{"formato sin número", "", "{prefijo}{letra}", "153B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ID_PREFIX_ENV, tt.prefix)
+			t.Setenv(ID_FORMAT_ENV, tt.format)
+			if got := GenerateID(3, "B"); got != tt.want {
+				t.Errorf("GenerateID = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateUniqueID(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		mounted    []MountedPartition
+		disk       string
+		wantID     string
+		wantLetter string
+		wantNumber int
+	}{
+		{
+			name:   "primer montaje",
+			disk:   "/discos/a.mia",
+			wantID: "151A", wantLetter: "A", wantNumber: 1,
+		},
+		{
+			name: "disco con letra",
+			mounted: []MountedPartition{
+				{ID: "151A", DiskPath: "/discos/a.mia", Letter: "A", Number: 1},
+				{ID: "151B", DiskPath: "/discos/b.mia", Letter: "B", Number: 1},
+			},
+			disk:   "/discos/a.mia",
+			wantID: "152A", wantLetter: "A", wantNumber: 2,
+		},
+		{
+			name: "reutiliza el número libre",
+			mounted: []MountedPartition{
+				{ID: "152A", DiskPath: "/discos/a.mia", Letter: "A", Number: 2},
+			},
+			disk:   "/discos/a.mia",
+			wantID: "151A", wantLetter: "A", wantNumber: 1,
+		},
+		{
+			name: "disco nuevo después de Z",
+			mounted: func() []MountedPartition {
+				var mounted []MountedPartition
+				for i := 0; i < 26; i++ {
+					letter := LetterForIndex(i)
+					mounted = append(mounted, MountedPartition{ID: "151" + letter, DiskPath: "/discos/" + letter, Letter: letter, Number: 1})
+				}
+				return mounted
+			}(),
+			disk:   "/discos/nuevo.mia",
+			wantID: "151AA", wantLetter: "AA", wantNumber: 1,
+		},
+		{
+			name:   "formato configurado",
+			format: "{letra}{numero}-{prefijo}",
+			mounted: []MountedPartition{
+				{ID: "A1-15", DiskPath: "/discos/a.mia", Letter: "A", Number: 1},
+			},
+			disk:   "/discos/a.mia",
+			wantID: "A2-15", wantLetter: "A", wantNumber: 2,
+		},
+		{
+			// Un ID guardado con otro formato puede coincidir con el que toca ahora
+			name: "ID ocupado por otro disco",
+			mounted: []MountedPartition{
+				{ID: "151b", DiskPath: "/discos/a.mia", Letter: "A", Number: 7},
+			},
+			disk:   "/discos/b.mia",
+			wantID: "152B", wantLetter: "B", wantNumber: 2,
+		},
+	}
+
+	defer func(mounted []MountedPartition) { MountedPartitions = mounted }(MountedPartitions)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ID_PREFIX_ENV, "")
+			t.Setenv(ID_FORMAT_ENV, tt.format)
+			MountedPartitions = tt.mounted
+
+			id, letter, number := GenerateUniqueID(tt.disk)
+			if id != tt.wantID || letter != tt.wantLetter || number != tt.wantNumber {
+				t.Errorf("GenerateUniqueID = (%q, %q, %d), se esperaba (%q, %q, %d)",
+					id, letter, number, tt.wantID, tt.wantLetter, tt.wantNumber)
+			}
+		})
+	}
+}
+
+func TestMountedPartitionUnmarshalLetter(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"letra como byte", `{"ID":"151A","Letter":65,"Number":1}`, "A", false},
+		{"letra como texto", `{"ID":"151AB","Letter":"AB","Number":1}`, "AB", false},
+		{"sin letra", `{"ID":"151A","Number":1}`, "", false},
+		{"letra inválida", `{"ID":"151A","Letter":true}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mp MountedPartition
+			err := json.Unmarshal([]byte(tt.data), &mp)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("se esperaba un error, se obtuvo la letra %q", mp.Letter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+			if mp.Letter != tt.want || mp.ID == "" {
+				t.Errorf("MountedPartition = %+v, se esperaba la letra %q", mp, tt.want)
+			}
+		})
+	}
+}
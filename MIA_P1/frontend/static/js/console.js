@@ -83,7 +83,31 @@ async function enviarComandos(comandos, outputConsole) {
 
                     const confirmar = await createConsoleDialog(data.mensaje);
 
-                    if (confirmar) {
+                    if (confirmar && (data.tipoConfirmacion === 'recuperarDisco' || data.tipoConfirmacion === 'desfragmentarDisco')) {
+                        // recoverdisk y defragdisk se confirman en el mismo endpoint del analizador
+                        try {
+                            const recoverResponse = await fetch('http://localhost:1921/analizar', {
+                                method: 'POST',
+                                headers: {
+                                    'Content-Type': 'application/json',
+                                },
+                                body: JSON.stringify({
+                                    tipoConfirmacion: data.tipoConfirmacion,
+                                    confirmar: true,
+                                    comando: data.comando,
+                                    path: data.path
+                                })
+                            });
+
+                            const recoverData = await recoverResponse.json();
+                            outputConsole.value += `${recoverData.mensaje}\n`;
+                            scrollToBottom(outputConsole);
+                        } catch (error) {
+                            outputConsole.value += `Error al procesar confirmación: ${error}\n`;
+                            scrollToBottom(outputConsole);
+                            console.error('Error:', error);
+                        }
+                    } else if (confirmar) {
                         try {
                             // Always use ext2-crear-directorios for mkfile operations
                             const isMkfileOperation = data.comando.toLowerCase().startsWith('mkfile');
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"MIA_P1/backend/DiskManager"
 	"MIA_P1/backend/routes"
 	"log"
 )
 
 func main() {
+	// Recuperar discos y particiones montadas de la ejecución anterior
+	if err := DiskManager.LoadState(); err != nil {
+		log.Printf("Error al cargar el estado guardado: %v", err)
+	}
+
 	r := routes.SetupRouter()
 
 	log.Printf("Servidor iniciado en http://localhost:1921")